@@ -0,0 +1,174 @@
+package gomysql
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// fieldCodec converts the values of a Go type the built-in representations do not cover. A
+// nil encode or decode falls back to the field's representation.
+type fieldCodec struct {
+	encode  func(value reflect.Value) (any, error)
+	decode  func(raw any, target reflect.Value) error
+	sqlType string // column type of a registered codec, empty for the representation's
+}
+
+var (
+	codecsMu sync.RWMutex
+	codecs   = make(map[reflect.Type]*fieldCodec)
+
+	valuerType          = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+	scannerType         = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// RegisterCodec stores fields of type T, and of *T, in columns of sqlType: encode turns a
+// value into one the database driver accepts and decode turns a scanned value back. NULL
+// decodes to T's zero value without calling decode. Codecs apply to structs registered after
+// them and take precedence over the driver.Valuer, sql.Scanner and encoding.TextMarshaler
+// implementations of T.
+func RegisterCodec[T any](encode func(T) (driver.Value, error), decode func(any) (T, error), sqlType string) {
+	if encode == nil || decode == nil || sqlType == "" {
+		panic("RegisterCodec requires encode, decode and a SQL type")
+	}
+
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+
+	codecs[reflect.TypeOf((*T)(nil)).Elem()] = &fieldCodec{
+		encode: func(value reflect.Value) (any, error) {
+			return encode(value.Interface().(T))
+		},
+		decode: func(raw any, target reflect.Value) error {
+			if raw == nil {
+				target.Set(reflect.Zero(target.Type()))
+				return nil
+			}
+
+			value, err := decode(raw)
+			if err != nil {
+				return err
+			}
+			target.Set(reflect.ValueOf(&value).Elem())
+			return nil
+		},
+		sqlType: sqlType,
+	}
+}
+
+// resolveFieldType returns the representation of a field's type and, for types converted by
+// a registered codec or their driver.Valuer, sql.Scanner or encoding.TextMarshaler methods,
+// the codec doing so. Valuer types of a basic kind keep its column type; other such types are
+// stored as text.
+func resolveFieldType(t reflect.Type) (TypeRepresentation, *fieldCodec, error) {
+	base := t
+	if base.Kind() == reflect.Pointer {
+		if base = base.Elem(); base.Kind() == reflect.Pointer {
+			return 0, nil, fmt.Errorf("unsupported nested pointer type %s", t)
+		}
+	}
+
+	if base == timeType {
+		return TypeRepTime, nil, nil
+	}
+
+	codecsMu.RLock()
+	registered := codecs[base]
+	codecsMu.RUnlock()
+	if registered != nil {
+		return TypeRepCustom, registered, nil
+	}
+
+	var (
+		codec fieldCodec
+		ptr   = reflect.PointerTo(base)
+	)
+
+	switch {
+	case ptr.Implements(valuerType):
+		codec.encode = encodeValuer
+	case ptr.Implements(textMarshalerType):
+		codec.encode = encodeText
+	}
+
+	switch {
+	case ptr.Implements(scannerType):
+		codec.decode = decodeScanner
+	case ptr.Implements(textUnmarshalerType):
+		codec.decode = decodeText
+	}
+
+	if codec.encode == nil && codec.decode == nil {
+		internalType, err := resolveInternalType(t)
+		return internalType, nil, err
+	}
+
+	// A basic kind keeps its column type unless it is written as text; the half without a
+	// method then uses the kind.
+	if basicKind(base) && (codec.encode == nil || ptr.Implements(valuerType)) {
+		internalType, err := resolveInternalType(base)
+		return internalType, &codec, err
+	}
+
+	if codec.encode == nil || codec.decode == nil {
+		return 0, nil, fmt.Errorf("type %s must implement driver.Valuer or encoding.TextMarshaler and sql.Scanner or encoding.TextUnmarshaler", base)
+	}
+
+	return TypeRepString, &codec, nil
+}
+
+func basicKind(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.String, reflect.Bool, reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// addressable returns v, or an addressable copy of it, so pointer-receiver methods apply.
+func addressable(v reflect.Value) reflect.Value {
+	if v.CanAddr() {
+		return v
+	}
+
+	copied := reflect.New(v.Type()).Elem()
+	copied.Set(v)
+	return copied
+}
+
+func encodeValuer(value reflect.Value) (any, error) {
+	return addressable(value).Addr().Interface().(driver.Valuer).Value()
+}
+
+func encodeText(value reflect.Value) (any, error) {
+	text, err := addressable(value).Addr().Interface().(encoding.TextMarshaler).MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return string(text), nil
+}
+
+func decodeScanner(raw any, target reflect.Value) error {
+	return target.Addr().Interface().(sql.Scanner).Scan(raw)
+}
+
+func decodeText(raw any, target reflect.Value) error {
+	target.Set(reflect.Zero(target.Type()))
+	if raw == nil {
+		return nil
+	}
+
+	text, err := sqlString(raw)
+	if err != nil {
+		return err
+	}
+	return target.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(text))
+}
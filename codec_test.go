@@ -0,0 +1,70 @@
+package gomysql
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"net/netip"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type codecLevel int
+
+func (l codecLevel) Value() (driver.Value, error) { return int64(l), nil }
+
+type codecName string
+
+func (n codecName) MarshalText() ([]byte, error) { return []byte(n), nil }
+
+func (n *codecName) UnmarshalText(text []byte) error {
+	*n = codecName(text)
+	return nil
+}
+
+type codecCelsius float64
+
+func TestResolveFieldType(t *testing.T) {
+	RegisterCodec(func(c codecCelsius) (driver.Value, error) {
+		return float64(c), nil
+	}, func(raw any) (codecCelsius, error) {
+		value, err := sqlFloat64(raw)
+		return codecCelsius(value), err
+	}, "REAL")
+
+	cases := []struct {
+		value any
+		want  TypeRepresentation
+		codec bool
+	}{
+		{0, TypeRepInt, false},
+		{codecLevel(0), TypeRepInt, true},
+		{codecName(""), TypeRepString, true},
+		{netip.Addr{}, TypeRepString, true},
+		{&netip.Addr{}, TypeRepString, true},
+		{sql.NullString{}, TypeRepString, true},
+		{codecCelsius(0), TypeRepCustom, true},
+		{struct{ A int }{}, TypeRepStructBlob, false},
+	}
+
+	for _, c := range cases {
+		internalType, codec, err := resolveFieldType(reflect.TypeOf(c.value))
+		assert.NoError(t, err)
+		assert.Equal(t, c.want, internalType, "%T", c.value)
+		assert.Equal(t, c.codec, codec != nil, "%T", c.value)
+	}
+
+	field := RegisteredStructField{Opts: SQLTagOpts{KeyName: "temp"}, Type: reflect.TypeOf(codecCelsius(0))}
+	field.InternalType, field.codec, _ = resolveFieldType(field.Type)
+	assert.Equal(t, "REAL", SQLite.columnType(field))
+	assert.Equal(t, "REAL", MySQL.columnType(field))
+
+	arg, err := normalizeValueForField(field, 21.5)
+	assert.NoError(t, err)
+	assert.Equal(t, 21.5, arg)
+
+	decoded, err := decodeSQLValue(field, 19.0)
+	assert.NoError(t, err)
+	assert.Equal(t, codecCelsius(19), decoded)
+}
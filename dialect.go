@@ -0,0 +1,247 @@
+package gomysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Dialect describes how a database engine is configured, how SQL is generated for it and
+// how its schema is introspected during migrations. Use one of the predeclared dialects.
+type Dialect interface {
+	Name() string
+
+	driverName() string
+	configure(db *sql.DB, opts DriverOptions) error
+	quoteIdent(name string) string
+	columnType(field RegisteredStructField) string
+	autoIncrementKeyword() string
+	inlineForeignKeys() bool
+	insertOrIgnoreSQL(table string, columns []string) string
+	upsertSQL(table string, columns, conflictColumns, updateColumns []string) string
+	supportsReturning() bool
+	supportsLastInsertID() bool
+	consecutiveInsertIDs() bool
+	maxBindParams() int
+	rebind(query string) string
+	bindValue(value any) any
+	columnTypeMatches(existing string, field RegisteredStructField) bool
+	tableColumns(ctx context.Context, q sqlExecutor, table string) ([]columnInfo, error)
+	tableForeignKeys(ctx context.Context, q sqlExecutor, table string) (map[string]foreignKeyInfo, error)
+	crossSchemaForeignKeys() bool
+	indexTarget(table, index string) (name, on string)
+	indexColumn(field RegisteredStructField) string
+	partialIndexes() bool
+	dropIndexSQL(table, index string) string
+	tableIndexes(ctx context.Context, q sqlExecutor, table string) ([]indexInfo, error)
+	jsonExtract(column, path string, kind jsonKind) string
+}
+
+// alterDialect is implemented by dialects that migrate tables in place with ALTER TABLE
+// instead of rebuilding them.
+type alterDialect interface {
+	renameColumnSQL(table, oldName, newName string) string
+	alterColumnSQL(table string, field RegisteredStructField) []string
+	dropColumnSQL(table, column string) string
+	dropPrimaryKeySQL(ctx context.Context, q sqlExecutor, table string) (string, error)
+	dropForeignKeySQL(table string, info foreignKeyInfo) string
+	addForeignKeySQL(table string, field RegisteredStructField) string
+	transactionalDDL() bool
+}
+
+// checkDialect is implemented by dialects that can report column CHECK constraints as they
+// were written, so Migrate can detect when they change.
+type checkDialect interface {
+	tableChecks(ctx context.Context, q sqlExecutor, table string) (map[string]string, error)
+}
+
+var (
+	SQLite   Dialect = sqliteDialect{}
+	MySQL    Dialect = mysqlDialect{}
+	Postgres Dialect = postgresDialect{}
+)
+
+func (o DriverOptions) dialect() Dialect {
+	if o.Dialect == nil {
+		return SQLite
+	}
+
+	return o.Dialect
+}
+
+// dialectExecutor adapts placeholders and query arguments to the dialect before they reach
+// database/sql, so statements and filters are always built with "?" placeholders.
+type dialectExecutor struct {
+	q       sqlExecutor
+	dialect Dialect
+}
+
+func (e dialectExecutor) bindArgs(args []any) []any {
+	if len(args) == 0 {
+		return args
+	}
+
+	bound := make([]any, len(args))
+	for i, arg := range args {
+		bound[i] = e.dialect.bindValue(arg)
+	}
+	return bound
+}
+
+func (e dialectExecutor) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return e.q.ExecContext(ctx, e.dialect.rebind(query), e.bindArgs(args)...)
+}
+
+func (e dialectExecutor) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return e.q.QueryContext(ctx, e.dialect.rebind(query), e.bindArgs(args)...)
+}
+
+func (e dialectExecutor) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return e.q.QueryRowContext(ctx, e.dialect.rebind(query), e.bindArgs(args)...)
+}
+
+type sqliteDialect struct{}
+
+func (sqliteDialect) Name() string {
+	return "sqlite"
+}
+
+func (sqliteDialect) driverName() string {
+	return "sqlite"
+}
+
+func (sqliteDialect) configure(db *sql.DB, opts DriverOptions) error {
+	db.SetMaxOpenConns(1)
+	db.SetMaxIdleConns(1)
+	return nil
+}
+
+func (sqliteDialect) quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func (sqliteDialect) columnType(field RegisteredStructField) string {
+	switch {
+	case field.InternalType == TypeRepCustom:
+		return field.codec.sqlType
+	case field.InternalType == TypeRepUint && field.Opts.PrimaryKey && field.Opts.AutoIncr:
+		// AUTOINCREMENT requires the rowid alias, declared exactly as INTEGER PRIMARY KEY.
+		return "INTEGER"
+	}
+	return typeNameString(field.InternalType)
+}
+
+func (sqliteDialect) autoIncrementKeyword() string {
+	return "AUTOINCREMENT"
+}
+
+func (sqliteDialect) inlineForeignKeys() bool {
+	return true
+}
+
+func (sqliteDialect) insertOrIgnoreSQL(table string, columns []string) string {
+	return fmt.Sprintf("INSERT OR IGNORE INTO %s (%s) VALUES (%s);", table, joinColumns(columns), placeholders(len(columns)))
+}
+
+func (sqliteDialect) upsertSQL(table string, columns, conflictColumns, updateColumns []string) string {
+	return onConflictUpsertSQL(table, columns, conflictColumns, updateColumns)
+}
+
+func (sqliteDialect) supportsReturning() bool {
+	return true
+}
+
+func (sqliteDialect) supportsLastInsertID() bool {
+	return true
+}
+
+// consecutiveInsertIDs is true: the write lock keeps the keys of a multi-row insert
+// consecutive, and LastInsertId reports the last row's key.
+func (sqliteDialect) consecutiveInsertIDs() bool {
+	return true
+}
+
+// maxBindParams is SQLITE_MAX_VARIABLE_NUMBER for SQLite 3.32 and later.
+func (sqliteDialect) maxBindParams() int {
+	return 32766
+}
+
+func (sqliteDialect) rebind(query string) string {
+	return query
+}
+
+func (sqliteDialect) bindValue(value any) any {
+	switch v := value.(type) {
+	case time.Time:
+		return formatSQLTimeValue(v)
+	case uint64:
+		if v > math.MaxInt64 {
+			return encodeUint64(v)
+		}
+	case uint:
+		if uint64(v) > math.MaxInt64 {
+			return encodeUint64(uint64(v))
+		}
+	}
+
+	return value
+}
+
+func (d sqliteDialect) columnTypeMatches(existing string, field RegisteredStructField) bool {
+	return normalizeSQLType(existing) == normalizeSQLType(d.columnType(field))
+}
+
+func (sqliteDialect) tableColumns(ctx context.Context, q sqlExecutor, table string) ([]columnInfo, error) {
+	return tableColumns(ctx, q, table)
+}
+
+func (sqliteDialect) crossSchemaForeignKeys() bool {
+	// REFERENCES names a table in the same database as the child table.
+	return false
+}
+
+func (d sqliteDialect) indexTarget(table, index string) (string, string) {
+	// The index is created in the table's database, named with its prefix, on the bare table.
+	schema, base := splitTableName(table)
+	if schema != "" {
+		index = schema + "." + index
+	}
+	return quoteTable(d, index), d.quoteIdent(base)
+}
+
+func (d sqliteDialect) indexColumn(field RegisteredStructField) string {
+	return d.quoteIdent(field.Opts.KeyName)
+}
+
+func (sqliteDialect) partialIndexes() bool {
+	return true
+}
+
+func (d sqliteDialect) dropIndexSQL(table, index string) string {
+	name, _ := d.indexTarget(table, index)
+	return fmt.Sprintf("DROP INDEX %s;", name)
+}
+
+func (sqliteDialect) tableIndexes(ctx context.Context, q sqlExecutor, table string) ([]indexInfo, error) {
+	return tableIndexes(ctx, q, table)
+}
+
+func (sqliteDialect) tableChecks(ctx context.Context, q sqlExecutor, table string) (map[string]string, error) {
+	return tableChecks(ctx, q, table)
+}
+
+func (sqliteDialect) tableForeignKeys(ctx context.Context, q sqlExecutor, table string) (map[string]foreignKeyInfo, error) {
+	return tableForeignKeys(ctx, q, table)
+}
+
+func (sqliteDialect) jsonExtract(column, path string, kind jsonKind) string {
+	return fmt.Sprintf("json_extract(%s, %s)", column, quoteString(path))
+}
+
+// quoteString renders s as a standard SQL string literal.
+func quoteString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
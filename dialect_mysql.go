@@ -0,0 +1,286 @@
+package gomysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const mysqlTimeLayout = "2006-01-02 15:04:05.000000"
+
+var mysqlIntDisplayWidth = regexp.MustCompile(`^(TINYINT|SMALLINT|MEDIUMINT|INT|BIGINT)\(\d+\)`)
+
+type mysqlDialect struct{}
+
+func (mysqlDialect) Name() string {
+	return "mysql"
+}
+
+func (mysqlDialect) driverName() string {
+	return "mysql"
+}
+
+func (mysqlDialect) configure(db *sql.DB, opts DriverOptions) error {
+	return nil
+}
+
+func (mysqlDialect) quoteIdent(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+func (mysqlDialect) columnType(field RegisteredStructField) string {
+	switch field.InternalType {
+	case TypeRepInt:
+		return "BIGINT"
+	case TypeRepUint:
+		return "BIGINT UNSIGNED"
+	case TypeRepString:
+		// TEXT columns cannot be keys without a prefix length.
+		if field.Opts.PrimaryKey || field.Opts.Unique || field.Opts.HasForeignKey() {
+			return "VARCHAR(255)"
+		}
+		return "TEXT"
+	case TypeRepBool:
+		return "BOOLEAN"
+	case TypeRepJSON:
+		return "TEXT"
+	case TypeRepCustom:
+		return field.codec.sqlType
+	case TypeRepArrayBlob, TypeRepMapBlob, TypeRepStructBlob, TypeRepPointer:
+		return "LONGBLOB"
+	case TypeRepFloat:
+		return "DOUBLE"
+	case TypeRepTime:
+		return "DATETIME(6)"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+func (mysqlDialect) autoIncrementKeyword() string {
+	return "AUTO_INCREMENT"
+}
+
+func (mysqlDialect) inlineForeignKeys() bool {
+	// InnoDB parses but ignores column-level REFERENCES clauses.
+	return false
+}
+
+func (mysqlDialect) insertOrIgnoreSQL(table string, columns []string) string {
+	return fmt.Sprintf("INSERT IGNORE INTO %s (%s) VALUES (%s);", table, joinColumns(columns), placeholders(len(columns)))
+}
+
+// upsertSQL ignores conflictColumns: ON DUPLICATE KEY UPDATE fires for any unique key. With
+// nothing to update the first conflict column is assigned to itself so the row is kept.
+func (mysqlDialect) upsertSQL(table string, columns, conflictColumns, updateColumns []string) string {
+	updates := make([]string, 0, len(updateColumns))
+	for _, column := range updateColumns {
+		updates = append(updates, fmt.Sprintf("%s = VALUES(%s)", column, column))
+	}
+
+	if len(updates) == 0 {
+		updates = append(updates, fmt.Sprintf("%s = %s", conflictColumns[0], conflictColumns[0]))
+	}
+
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) ON DUPLICATE KEY UPDATE %s;", table, joinColumns(columns), placeholders(len(columns)), strings.Join(updates, ", "))
+}
+
+func (mysqlDialect) supportsReturning() bool {
+	return false
+}
+
+func (mysqlDialect) supportsLastInsertID() bool {
+	return true
+}
+
+// consecutiveInsertIDs is false: auto_increment_increment above 1 or interleaved lock mode
+// under concurrent writers leave gaps between the keys of one multi-row insert.
+func (mysqlDialect) consecutiveInsertIDs() bool {
+	return false
+}
+
+func (mysqlDialect) maxBindParams() int {
+	return 65535
+}
+
+func (mysqlDialect) rebind(query string) string {
+	return query
+}
+
+func (mysqlDialect) bindValue(value any) any {
+	if t, ok := value.(time.Time); ok {
+		return t.UTC().Format(mysqlTimeLayout)
+	}
+
+	return value
+}
+
+func (d mysqlDialect) columnTypeMatches(existing string, field RegisteredStructField) bool {
+	normalized := normalizeSQLType(existing)
+	if normalized == "TINYINT(1)" {
+		normalized = "BOOLEAN"
+	}
+	normalized = mysqlIntDisplayWidth.ReplaceAllString(normalized, "$1")
+
+	return normalized == normalizeSQLType(d.columnType(field))
+}
+
+func (mysqlDialect) tableColumns(ctx context.Context, q sqlExecutor, table string) ([]columnInfo, error) {
+	schema, base := splitTableName(table)
+	schemaSQL, args := schemaPredicate(schema, "DATABASE()")
+
+	rows, err := q.QueryContext(ctx, fmt.Sprintf("SELECT c.COLUMN_NAME, c.COLUMN_TYPE, COALESCE(k.ORDINAL_POSITION, 0), c.COLUMN_DEFAULT "+
+		"FROM information_schema.COLUMNS c "+
+		"LEFT JOIN information_schema.KEY_COLUMN_USAGE k ON k.TABLE_SCHEMA = c.TABLE_SCHEMA AND k.TABLE_NAME = c.TABLE_NAME AND k.COLUMN_NAME = c.COLUMN_NAME AND k.CONSTRAINT_NAME = 'PRIMARY' "+
+		"WHERE c.TABLE_SCHEMA = %s AND c.TABLE_NAME = ? ORDER BY c.ORDINAL_POSITION;", schemaSQL), append(args, base)...)
+	if err != nil {
+		return nil, fmt.Errorf("describe table %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	var columns []columnInfo
+	for rows.Next() {
+		var col columnInfo
+		if err := rows.Scan(&col.Name, &col.Type, &col.PrimaryKey, &col.Default); err != nil {
+			return nil, fmt.Errorf("scan table info %s: %w", table, err)
+		}
+		columns = append(columns, col)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate table info %s: %w", table, err)
+	}
+
+	return columns, nil
+}
+
+func (mysqlDialect) crossSchemaForeignKeys() bool {
+	return true
+}
+
+func (d mysqlDialect) indexTarget(table, index string) (string, string) {
+	return d.quoteIdent(index), quoteTable(d, table)
+}
+
+func (d mysqlDialect) indexColumn(field RegisteredStructField) string {
+	// TEXT columns can only be indexed on a prefix.
+	if d.columnType(field) == "TEXT" {
+		return d.quoteIdent(field.Opts.KeyName) + "(255)"
+	}
+	return d.quoteIdent(field.Opts.KeyName)
+}
+
+func (mysqlDialect) partialIndexes() bool {
+	return false
+}
+
+func (d mysqlDialect) dropIndexSQL(table, index string) string {
+	return fmt.Sprintf("DROP INDEX %s ON %s;", d.quoteIdent(index), quoteTable(d, table))
+}
+
+func (mysqlDialect) tableIndexes(ctx context.Context, q sqlExecutor, table string) ([]indexInfo, error) {
+	// Indexes backing the primary key, unique constraints and foreign keys are left out.
+	schema, base := splitTableName(table)
+	schemaSQL, args := schemaPredicate(schema, "DATABASE()")
+
+	rows, err := q.QueryContext(ctx, fmt.Sprintf("SELECT s.INDEX_NAME, s.NON_UNIQUE, s.COLUMN_NAME FROM information_schema.STATISTICS s "+
+		"WHERE s.TABLE_SCHEMA = %s AND s.TABLE_NAME = ? AND s.INDEX_NAME <> 'PRIMARY' "+
+		"AND s.INDEX_NAME NOT IN (SELECT c.CONSTRAINT_NAME FROM information_schema.TABLE_CONSTRAINTS c WHERE c.TABLE_SCHEMA = s.TABLE_SCHEMA AND c.TABLE_NAME = s.TABLE_NAME) "+
+		"AND s.INDEX_NAME NOT IN (SELECT k.COLUMN_NAME FROM information_schema.KEY_COLUMN_USAGE k "+
+		"WHERE k.TABLE_SCHEMA = s.TABLE_SCHEMA AND k.TABLE_NAME = s.TABLE_NAME AND k.REFERENCED_TABLE_NAME IS NOT NULL) "+
+		"ORDER BY s.INDEX_NAME, s.SEQ_IN_INDEX;", schemaSQL), append(args, base)...)
+	if err != nil {
+		return nil, fmt.Errorf("describe indexes %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	var indexes []indexInfo
+	for rows.Next() {
+		var (
+			name      string
+			nonUnique bool
+			column    string
+		)
+
+		if err := rows.Scan(&name, &nonUnique, &column); err != nil {
+			return nil, fmt.Errorf("scan index info %s: %w", table, err)
+		}
+
+		if len(indexes) == 0 || indexes[len(indexes)-1].Name != name {
+			indexes = append(indexes, indexInfo{Name: name, Unique: !nonUnique})
+		}
+		indexes[len(indexes)-1].Columns = append(indexes[len(indexes)-1].Columns, column)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate index info %s: %w", table, err)
+	}
+
+	return indexes, nil
+}
+
+func (mysqlDialect) tableForeignKeys(ctx context.Context, q sqlExecutor, table string) (map[string]foreignKeyInfo, error) {
+	schema, base := splitTableName(table)
+	schemaSQL, args := schemaPredicate(schema, "DATABASE()")
+
+	rows, err := q.QueryContext(ctx, fmt.Sprintf("SELECT k.CONSTRAINT_NAME, k.COLUMN_NAME, k.REFERENCED_TABLE_NAME, k.REFERENCED_COLUMN_NAME, r.DELETE_RULE, r.UPDATE_RULE "+
+		"FROM information_schema.KEY_COLUMN_USAGE k "+
+		"JOIN information_schema.REFERENTIAL_CONSTRAINTS r ON r.CONSTRAINT_SCHEMA = k.TABLE_SCHEMA AND r.TABLE_NAME = k.TABLE_NAME AND r.CONSTRAINT_NAME = k.CONSTRAINT_NAME "+
+		"WHERE k.TABLE_SCHEMA = %s AND k.TABLE_NAME = ? AND k.REFERENCED_TABLE_NAME IS NOT NULL;", schemaSQL), append(args, base)...)
+	if err != nil {
+		return nil, fmt.Errorf("describe foreign keys %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	foreignKeys := make(map[string]foreignKeyInfo)
+	for rows.Next() {
+		var info foreignKeyInfo
+		if err := rows.Scan(&info.Name, &info.From, &info.Table, &info.To, &info.OnDelete, &info.OnUpdate); err != nil {
+			return nil, fmt.Errorf("scan foreign key info %s: %w", table, err)
+		}
+		foreignKeys[normalizeIdentifier(info.From)] = info
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate foreign key info %s: %w", table, err)
+	}
+
+	return foreignKeys, nil
+}
+
+func (d mysqlDialect) renameColumnSQL(table, oldName, newName string) string {
+	return fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s;", quoteTable(d, table), d.quoteIdent(oldName), d.quoteIdent(newName))
+}
+
+func (d mysqlDialect) alterColumnSQL(table string, field RegisteredStructField) []string {
+	return []string{fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s;", quoteTable(d, table), columnDefinition(d, field, false))}
+}
+
+func (d mysqlDialect) dropPrimaryKeySQL(_ context.Context, _ sqlExecutor, table string) (string, error) {
+	return fmt.Sprintf("ALTER TABLE %s DROP PRIMARY KEY;", quoteTable(d, table)), nil
+}
+
+func (d mysqlDialect) dropColumnSQL(table, column string) string {
+	return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;", quoteTable(d, table), d.quoteIdent(column))
+}
+
+func (d mysqlDialect) dropForeignKeySQL(table string, info foreignKeyInfo) string {
+	return fmt.Sprintf("ALTER TABLE %s DROP FOREIGN KEY %s;", quoteTable(d, table), d.quoteIdent(info.Name))
+}
+
+func (d mysqlDialect) addForeignKeySQL(table string, field RegisteredStructField) string {
+	return fmt.Sprintf("ALTER TABLE %s ADD %s;", quoteTable(d, table), foreignKeyConstraint(d, field))
+}
+
+func (mysqlDialect) transactionalDDL() bool {
+	// MySQL commits implicitly around every DDL statement.
+	return false
+}
+
+// jsonExtract returns a JSON value; MySQL converts the other operand of a comparison to JSON.
+func (mysqlDialect) jsonExtract(column, path string, kind jsonKind) string {
+	return fmt.Sprintf("JSON_EXTRACT(%s, %s)", column, quoteString(strings.ReplaceAll(path, `\`, `\\`)))
+}
@@ -0,0 +1,390 @@
+package gomysql
+
+import (
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type MySQLTeam struct {
+	ID   int    `gomysql:"id,primary,increment"`
+	Name string `gomysql:"name,unique"`
+}
+
+type MySQLPlayer struct {
+	ID       int       `gomysql:"id,primary,increment"`
+	TeamID   int       `gomysql:"team_id,fkey:MySQLTeam.id"`
+	Nickname string    `gomysql:"nickname"`
+	Active   bool      `gomysql:"active"`
+	Joined   time.Time `gomysql:"joined"`
+	Tags     []string  `gomysql:"tags"`
+}
+
+func TestMySQLCreateTableSQL(t *testing.T) {
+	db, fake := openFakeDriver(t, MySQL)
+
+	if _, err := RegisterOn(db, MySQLTeam{}); err != nil {
+		t.Fatalf("failed to register team: %v", err)
+	}
+
+	if _, err := RegisterOn(db, MySQLPlayer{}); err != nil {
+		t.Fatalf("failed to register player: %v", err)
+	}
+
+	expected := []string{
+		"CREATE TABLE IF NOT EXISTS `MySQLTeam` (`id` BIGINT PRIMARY KEY AUTO_INCREMENT, `name` VARCHAR(255) UNIQUE);",
+		"CREATE TABLE IF NOT EXISTS `MySQLPlayer` (`id` BIGINT PRIMARY KEY AUTO_INCREMENT, `team_id` BIGINT, `nickname` TEXT, `active` BOOLEAN, `joined` DATETIME(6), `tags` LONGBLOB, FOREIGN KEY (`team_id`) REFERENCES `MySQLTeam`(`id`));",
+	}
+
+	if got := fake.queries(); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("unexpected create statements:\n got: %q\nwant: %q", got, expected)
+	}
+}
+
+func TestMySQLInsertAndSelectSQL(t *testing.T) {
+	db, fake := openFakeDriver(t, MySQL)
+
+	players, err := RegisterOn(db, MySQLPlayer{})
+	if err != nil {
+		t.Fatalf("failed to register player: %v", err)
+	}
+
+	fake.reset()
+	joined := time.Date(2026, 2, 3, 4, 5, 6, 789123456, time.FixedZone("EST", -5*60*60))
+	player := &MySQLPlayer{TeamID: 7, Nickname: "ace", Active: true, Joined: joined}
+	if err := players.Insert(player); err != nil {
+		t.Fatalf("failed to insert player: %v", err)
+	}
+
+	insert := fake.last()
+	expectedSQL := "INSERT INTO `MySQLPlayer` (`team_id`, `nickname`, `active`, `joined`, `tags`) VALUES (?, ?, ?, ?, ?);"
+	if insert.Query != expectedSQL {
+		t.Fatalf("unexpected insert statement:\n got: %s\nwant: %s", insert.Query, expectedSQL)
+	}
+
+	if insert.Args[3] != "2026-02-03 09:05:06.789123" {
+		t.Fatalf("expected MySQL DATETIME literal, got %#v", insert.Args[3])
+	}
+
+	if player.ID != 1 {
+		t.Fatalf("expected LastInsertId to be assigned, got %d", player.ID)
+	}
+
+	fake.respond("SELECT `id`, `team_id`", []string{"id", "team_id", "nickname", "active", "joined", "tags"},
+		[]driver.Value{int64(1), int64(7), []byte("ace"), int64(1), []byte("2026-02-03 09:05:06.789123"), nil})
+
+	got, err := players.Select(1)
+	if err != nil {
+		t.Fatalf("failed to select player: %v", err)
+	}
+
+	if fake.last().Query != "SELECT `id`, `team_id`, `nickname`, `active`, `joined`, `tags` FROM `MySQLPlayer` WHERE `id` = ?;" {
+		t.Fatalf("unexpected select statement: %s", fake.last().Query)
+	}
+
+	if got.ID != 1 || got.Nickname != "ace" || !got.Active || !got.Joined.Equal(joined.Truncate(time.Microsecond)) {
+		t.Fatalf("unexpected decoded player: %+v", got)
+	}
+}
+
+func TestMySQLInsertManyReadsKeysPerRow(t *testing.T) {
+	db, fake := openFakeDriver(t, MySQL)
+
+	teams, err := RegisterOn(db, MySQLTeam{})
+	if err != nil {
+		t.Fatalf("failed to register team: %v", err)
+	}
+
+	// auto_increment_increment = 2 leaves gaps between the keys of one statement.
+	fake.idStep = 2
+	fake.reset()
+
+	items := []*MySQLTeam{{Name: "red"}, {Name: "blue"}, {Name: "green"}}
+	if err := teams.InsertMany(items, InsertManyOptions{}); err != nil {
+		t.Fatalf("failed to insert teams: %v", err)
+	}
+
+	for i, want := range []int{2, 4, 6} {
+		if items[i].ID != want {
+			t.Fatalf("expected team %d to get key %d, got %d", i, want, items[i].ID)
+		}
+	}
+
+	expected := []string{
+		"BEGIN",
+		"SAVEPOINT gomysql_sp_1;",
+		"INSERT INTO `MySQLTeam` (`name`) VALUES (?);",
+		"INSERT INTO `MySQLTeam` (`name`) VALUES (?);",
+		"INSERT INTO `MySQLTeam` (`name`) VALUES (?);",
+		"RELEASE SAVEPOINT gomysql_sp_1;",
+		"COMMIT",
+	}
+	if got := fake.queries(); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("unexpected insert statements:\n got: %q\nwant: %q", got, expected)
+	}
+}
+
+func TestMySQLDeleteWithLimitUsesDerivedTable(t *testing.T) {
+	db, fake := openFakeDriver(t, MySQL)
+
+	players, err := RegisterOn(db, MySQLPlayer{})
+	if err != nil {
+		t.Fatalf("failed to register player: %v", err)
+	}
+
+	if _, err := players.DeleteWithFilter(NewFilter().Ordering(players.FieldByGoName("Joined"), true).Limit(10)); err != nil {
+		t.Fatalf("failed to delete with filter: %v", err)
+	}
+
+	expected := "DELETE FROM `MySQLPlayer` WHERE `id` IN (SELECT `id` FROM (SELECT `id` FROM `MySQLPlayer` ORDER BY `joined` ASC LIMIT 10) AS filtered_rows);"
+	if got := fake.last().Query; got != expected {
+		t.Fatalf("unexpected delete statement:\n got: %s\nwant: %s", got, expected)
+	}
+}
+
+func TestMySQLUpdateReturningUnsupported(t *testing.T) {
+	db, _ := openFakeDriver(t, MySQL)
+
+	players, err := RegisterOn(db, MySQLPlayer{})
+	if err != nil {
+		t.Fatalf("failed to register player: %v", err)
+	}
+
+	_, err = players.UpdateWithFilterReturning(nil, []*RegisteredStructField{players.FieldByGoName("Nickname")}, SetField(players.FieldByGoName("Nickname"), "x"))
+	if !errors.Is(err, ErrUnsupportedByDialect) {
+		t.Fatalf("expected ErrUnsupportedByDialect, got %v", err)
+	}
+}
+
+func TestMySQLMigrateAltersInPlace(t *testing.T) {
+	db, fake := openFakeDriver(t, MySQL)
+
+	players, err := RegisterOn(db, MySQLPlayer{})
+	if err != nil {
+		t.Fatalf("failed to register player: %v", err)
+	}
+
+	fake.respond("information_schema.COLUMNS", []string{"COLUMN_NAME", "COLUMN_TYPE", "ORDINAL_POSITION", "COLUMN_DEFAULT"},
+		[]driver.Value{"id", "bigint(20)", int64(1), nil},
+		[]driver.Value{"team_id", "int(11)", int64(0), nil},
+		[]driver.Value{"nick", "text", int64(0), nil},
+		[]driver.Value{"active", "tinyint(1)", int64(0), nil},
+		[]driver.Value{"joined", "datetime(6)", int64(0), nil},
+		[]driver.Value{"legacy", "text", int64(0), nil},
+	)
+	fake.respond("r.DELETE_RULE", []string{"CONSTRAINT_NAME", "COLUMN_NAME", "REFERENCED_TABLE_NAME", "REFERENCED_COLUMN_NAME", "DELETE_RULE", "UPDATE_RULE"},
+		[]driver.Value{"fk_old_team", "team_id", "OldTeam", "id", "RESTRICT", "RESTRICT"},
+	)
+	fake.reset()
+
+	if _, err := players.Migrate(MigrationOptions{Renames: map[string]string{"nick": "nickname"}}); !errors.Is(err, ErrMigrationDestructive) {
+		t.Fatalf("expected destructive migration error, got %v", err)
+	}
+
+	fake.reset()
+	report, err := players.Migrate(MigrationOptions{AllowDestructive: true, Renames: map[string]string{"nick": "nickname"}})
+	if err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+
+	if !report.Altered || report.Rebuilt {
+		t.Fatalf("expected in-place alter, got %+v", report)
+	}
+
+	expected := []string{
+		"SELECT c.COLUMN_NAME, c.COLUMN_TYPE, COALESCE(k.ORDINAL_POSITION, 0), c.COLUMN_DEFAULT FROM information_schema.COLUMNS c " +
+			"LEFT JOIN information_schema.KEY_COLUMN_USAGE k ON k.TABLE_SCHEMA = c.TABLE_SCHEMA AND k.TABLE_NAME = c.TABLE_NAME AND k.COLUMN_NAME = c.COLUMN_NAME AND k.CONSTRAINT_NAME = 'PRIMARY' " +
+			"WHERE c.TABLE_SCHEMA = DATABASE() AND c.TABLE_NAME = ? ORDER BY c.ORDINAL_POSITION;",
+		"SELECT k.CONSTRAINT_NAME, k.COLUMN_NAME, k.REFERENCED_TABLE_NAME, k.REFERENCED_COLUMN_NAME, r.DELETE_RULE, r.UPDATE_RULE " +
+			"FROM information_schema.KEY_COLUMN_USAGE k " +
+			"JOIN information_schema.REFERENTIAL_CONSTRAINTS r ON r.CONSTRAINT_SCHEMA = k.TABLE_SCHEMA AND r.TABLE_NAME = k.TABLE_NAME AND r.CONSTRAINT_NAME = k.CONSTRAINT_NAME " +
+			"WHERE k.TABLE_SCHEMA = DATABASE() AND k.TABLE_NAME = ? AND k.REFERENCED_TABLE_NAME IS NOT NULL;",
+		"SELECT s.INDEX_NAME, s.NON_UNIQUE, s.COLUMN_NAME FROM information_schema.STATISTICS s " +
+			"WHERE s.TABLE_SCHEMA = DATABASE() AND s.TABLE_NAME = ? AND s.INDEX_NAME <> 'PRIMARY' " +
+			"AND s.INDEX_NAME NOT IN (SELECT c.CONSTRAINT_NAME FROM information_schema.TABLE_CONSTRAINTS c WHERE c.TABLE_SCHEMA = s.TABLE_SCHEMA AND c.TABLE_NAME = s.TABLE_NAME) " +
+			"AND s.INDEX_NAME NOT IN (SELECT k.COLUMN_NAME FROM information_schema.KEY_COLUMN_USAGE k " +
+			"WHERE k.TABLE_SCHEMA = s.TABLE_SCHEMA AND k.TABLE_NAME = s.TABLE_NAME AND k.REFERENCED_TABLE_NAME IS NOT NULL) " +
+			"ORDER BY s.INDEX_NAME, s.SEQ_IN_INDEX;",
+		"ALTER TABLE `MySQLPlayer` RENAME COLUMN `nick` TO `nickname`;",
+		"ALTER TABLE `MySQLPlayer` DROP COLUMN `legacy`;",
+		"ALTER TABLE `MySQLPlayer` DROP FOREIGN KEY `fk_old_team`;",
+		"ALTER TABLE `MySQLPlayer` MODIFY COLUMN `team_id` BIGINT;",
+		"ALTER TABLE `MySQLPlayer` ADD FOREIGN KEY (`team_id`) REFERENCES `MySQLTeam`(`id`);",
+		"ALTER TABLE `MySQLPlayer` ADD COLUMN `tags` LONGBLOB;",
+	}
+
+	if got := fake.queries(); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("unexpected migration statements:\n got: %q\nwant: %q", got, expected)
+	}
+}
+
+func TestMySQLInsertOrIgnoreAndUpsertSQL(t *testing.T) {
+	db, fake := openFakeDriver(t, MySQL)
+
+	players, err := RegisterOn(db, MySQLPlayer{})
+	if err != nil {
+		t.Fatalf("failed to register player: %v", err)
+	}
+
+	fake.reset()
+	if _, err := players.InsertOrIgnore(&MySQLPlayer{TeamID: 1, Nickname: "ace"}); err != nil {
+		t.Fatalf("failed to insert player: %v", err)
+	}
+
+	expected := "INSERT IGNORE INTO `MySQLPlayer` (`team_id`, `nickname`, `active`, `joined`, `tags`) VALUES (?, ?, ?, ?, ?);"
+	if got := fake.last().Query; got != expected {
+		t.Fatalf("unexpected insert statement:\n got: %s\nwant: %s", got, expected)
+	}
+
+	fake.reset()
+	inserted, err := players.Upsert(&MySQLPlayer{TeamID: 1, Nickname: "ace"},
+		[]*RegisteredStructField{players.FieldByGoName("Nickname")}, players.FieldByGoName("Active"))
+	if err != nil {
+		t.Fatalf("failed to upsert player: %v", err)
+	}
+
+	if !inserted {
+		t.Fatalf("expected upsert without an existing row to insert")
+	}
+
+	statements := []string{
+		"BEGIN",
+		"SELECT `id` FROM `MySQLPlayer` WHERE `nickname` = ?;",
+		"INSERT INTO `MySQLPlayer` (`team_id`, `nickname`, `active`, `joined`, `tags`) VALUES (?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE `active` = VALUES(`active`);",
+		"COMMIT",
+	}
+	if got := fake.queries(); !reflect.DeepEqual(got, statements) {
+		t.Fatalf("unexpected upsert statements:\n got: %q\nwant: %q", got, statements)
+	}
+}
+
+type MySQLMembership struct {
+	TeamID   int    `gomysql:"team_id,primary"`
+	PlayerID int    `gomysql:"player_id,primary"`
+	Role     string `gomysql:"role"`
+}
+
+func TestMySQLCompositePrimaryKey(t *testing.T) {
+	db, fake := openFakeDriver(t, MySQL)
+
+	memberships, err := RegisterOn(db, MySQLMembership{})
+	if err != nil {
+		t.Fatalf("failed to register membership: %v", err)
+	}
+
+	expectedCreate := "CREATE TABLE IF NOT EXISTS `MySQLMembership` (`team_id` BIGINT, `player_id` BIGINT, `role` TEXT, PRIMARY KEY (`team_id`, `player_id`));"
+	if got := fake.last().Query; got != expectedCreate {
+		t.Fatalf("unexpected create statement:\n got: %s\nwant: %s", got, expectedCreate)
+	}
+
+	fake.respond("information_schema.COLUMNS", []string{"COLUMN_NAME", "COLUMN_TYPE", "ORDINAL_POSITION", "COLUMN_DEFAULT"},
+		[]driver.Value{"team_id", "bigint", int64(1), nil},
+		[]driver.Value{"player_id", "bigint", int64(0), nil},
+		[]driver.Value{"role", "text", int64(0), nil},
+	)
+	fake.reset()
+
+	report, err := memberships.Migrate(MigrationOptions{AllowDestructive: true})
+	if err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+
+	if !report.PrimaryKeyChanged {
+		t.Fatalf("expected primary key change to be reported")
+	}
+
+	got := fake.queries()
+	expected := []string{
+		"ALTER TABLE `MySQLMembership` DROP PRIMARY KEY;",
+		"ALTER TABLE `MySQLMembership` ADD PRIMARY KEY (`team_id`, `player_id`);",
+	}
+	if !reflect.DeepEqual(got[len(got)-2:], expected) {
+		t.Fatalf("unexpected migration statements:\n got: %q\nwant: %q", got, expected)
+	}
+}
+
+type MySQLEvent struct {
+	ID     int    `gomysql:"id,primary,increment"`
+	Kind   string `gomysql:"kind,index:event_kind_day"`
+	Day    int    `gomysql:"day,index:event_kind_day"`
+	Source string `gomysql:"source,index"`
+}
+
+type MySQLPartialEvent struct {
+	ID   int    `gomysql:"id,primary,increment"`
+	Kind string `gomysql:"kind"`
+}
+
+func (MySQLPartialEvent) Indexes() []Index {
+	return []Index{{Name: "open_events", Columns: []string{"kind"}, Where: "kind <> 'closed'"}}
+}
+
+func TestMySQLIndexes(t *testing.T) {
+	db, fake := openFakeDriver(t, MySQL)
+
+	fake.respond("information_schema.COLUMNS", []string{"COLUMN_NAME", "COLUMN_TYPE", "ORDINAL_POSITION", "COLUMN_DEFAULT"},
+		[]driver.Value{"id", "bigint", int64(1), nil},
+		[]driver.Value{"kind", "text", int64(0), nil},
+		[]driver.Value{"day", "bigint", int64(0), nil},
+		[]driver.Value{"source", "text", int64(0), nil},
+	)
+
+	events, err := RegisterOn(db, MySQLEvent{})
+	if err != nil {
+		t.Fatalf("failed to register event: %v", err)
+	}
+
+	got := fake.queries()
+	expectedCreate := []string{
+		"CREATE INDEX `event_kind_day` ON `MySQLEvent` (`kind`(255), `day`);",
+		"CREATE INDEX `MySQLEvent_source_idx` ON `MySQLEvent` (`source`(255));",
+	}
+	if !reflect.DeepEqual(got[len(got)-2:], expectedCreate) {
+		t.Fatalf("unexpected index statements:\n got: %q\nwant: %q", got, expectedCreate)
+	}
+
+	fake.respond("information_schema.STATISTICS", []string{"INDEX_NAME", "NON_UNIQUE", "COLUMN_NAME"},
+		[]driver.Value{"MySQLEvent_source_idx", int64(1), "source"},
+		[]driver.Value{"event_kind_day", int64(1), "kind"},
+		[]driver.Value{"MySQLEvent_day_idx", int64(1), "day"},
+		[]driver.Value{"MySQLEvent_kind_unique", int64(0), "kind"},
+		[]driver.Value{"MySQLEvent_stale_idx", int64(1), "day"},
+		[]driver.Value{"manual_day", int64(1), "day"},
+	)
+	fake.reset()
+
+	if _, err := events.Migrate(MigrationOptions{}); !errors.Is(err, ErrMigrationDestructive) {
+		t.Fatalf("expected dropping an index to require AllowDestructive, got %v", err)
+	}
+
+	fake.reset()
+	report, err := events.Migrate(MigrationOptions{AllowDestructive: true})
+	if err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+
+	// The tags of day and kind once generated MySQLEvent_day_idx and MySQLEvent_kind_unique.
+	// No column named stale exists, so MySQLEvent_stale_idx was made by hand like manual_day
+	// and is left alone.
+	if !reflect.DeepEqual(report.DroppedIndexes, []string{"MySQLEvent_day_idx", "MySQLEvent_kind_unique", "event_kind_day"}) ||
+		!reflect.DeepEqual(report.AddedIndexes, []string{"event_kind_day"}) {
+		t.Fatalf("unexpected index changes: %+v", report)
+	}
+
+	got = fake.queries()
+	expected := []string{
+		"DROP INDEX `MySQLEvent_day_idx` ON `MySQLEvent`;",
+		"DROP INDEX `MySQLEvent_kind_unique` ON `MySQLEvent`;",
+		"DROP INDEX `event_kind_day` ON `MySQLEvent`;",
+		"CREATE INDEX `event_kind_day` ON `MySQLEvent` (`kind`(255), `day`);",
+	}
+	if !reflect.DeepEqual(got[len(got)-4:], expected) {
+		t.Fatalf("unexpected migration statements:\n got: %q\nwant: %q", got, expected)
+	}
+
+	if _, err := RegisterOn(db, MySQLPartialEvent{}); !errors.Is(err, ErrUnsupportedByDialect) {
+		t.Fatalf("expected partial index to be rejected on MySQL, got %v", err)
+	}
+}
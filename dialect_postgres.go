@@ -0,0 +1,399 @@
+package gomysql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+type postgresDialect struct{}
+
+func (postgresDialect) Name() string {
+	return "postgres"
+}
+
+func (postgresDialect) driverName() string {
+	return "pgx"
+}
+
+func (postgresDialect) configure(db *sql.DB, opts DriverOptions) error {
+	return nil
+}
+
+func (postgresDialect) quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func (postgresDialect) columnType(field RegisteredStructField) string {
+	switch field.InternalType {
+	case TypeRepInt, TypeRepUint:
+		if field.Opts.PrimaryKey && field.Opts.AutoIncr {
+			return "BIGSERIAL"
+		}
+		return "BIGINT"
+	case TypeRepString:
+		return "TEXT"
+	case TypeRepBool:
+		return "BOOLEAN"
+	case TypeRepJSON:
+		return "TEXT"
+	case TypeRepCustom:
+		return field.codec.sqlType
+	case TypeRepArrayBlob, TypeRepMapBlob, TypeRepStructBlob, TypeRepPointer:
+		return "BYTEA"
+	case TypeRepFloat:
+		return "DOUBLE PRECISION"
+	case TypeRepTime:
+		return "TIMESTAMPTZ"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+func (postgresDialect) autoIncrementKeyword() string {
+	// Auto-increment keys are declared with the BIGSERIAL column type instead.
+	return ""
+}
+
+func (postgresDialect) inlineForeignKeys() bool {
+	return true
+}
+
+func (postgresDialect) insertOrIgnoreSQL(table string, columns []string) string {
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) ON CONFLICT DO NOTHING;", table, joinColumns(columns), placeholders(len(columns)))
+}
+
+func (postgresDialect) upsertSQL(table string, columns, conflictColumns, updateColumns []string) string {
+	return onConflictUpsertSQL(table, columns, conflictColumns, updateColumns)
+}
+
+func (postgresDialect) supportsReturning() bool {
+	return true
+}
+
+func (postgresDialect) supportsLastInsertID() bool {
+	return false
+}
+
+// consecutiveInsertIDs is never consulted: keys are read back with RETURNING.
+func (postgresDialect) consecutiveInsertIDs() bool {
+	return false
+}
+
+func (postgresDialect) maxBindParams() int {
+	return 65535
+}
+
+// rebind numbers "?" placeholders as $1, $2, ... while leaving quoted strings and
+// identifiers untouched.
+func (postgresDialect) rebind(query string) string {
+	if !strings.Contains(query, "?") {
+		return query
+	}
+
+	var (
+		out   strings.Builder
+		n     int
+		quote byte
+	)
+
+	out.Grow(len(query) + 8)
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '?':
+			n++
+			out.WriteByte('$')
+			out.WriteString(strconv.Itoa(n))
+			continue
+		}
+		out.WriteByte(c)
+	}
+
+	return out.String()
+}
+
+// bindValue replaces unsigned integers above math.MaxInt64, which BIGINT columns cannot hold,
+// with a value that fails to bind with an overflow error.
+func (postgresDialect) bindValue(value any) any {
+	switch v := value.(type) {
+	case uint64:
+		if v > math.MaxInt64 {
+			return bigintOverflow(v)
+		}
+	case uint:
+		if uint64(v) > math.MaxInt64 {
+			return bigintOverflow(v)
+		}
+	}
+
+	return value
+}
+
+// bigintOverflow is bound in place of an unsigned integer too large for a PostgreSQL BIGINT.
+// database/sql reports its Value error for the statement.
+type bigintOverflow uint64
+
+func (v bigintOverflow) Value() (driver.Value, error) {
+	return nil, fmt.Errorf("%d overflows PostgreSQL BIGINT", uint64(v))
+}
+
+func (d postgresDialect) columnTypeMatches(existing string, field RegisteredStructField) bool {
+	var canonical = func(typeName string) string {
+		switch normalized := normalizeSQLType(typeName); normalized {
+		case "BIGSERIAL", "INT8":
+			return "BIGINT"
+		case "TIMESTAMPTZ":
+			return "TIMESTAMP WITH TIME ZONE"
+		case "FLOAT8":
+			return "DOUBLE PRECISION"
+		case "BOOL":
+			return "BOOLEAN"
+		default:
+			return normalized
+		}
+	}
+
+	return canonical(existing) == canonical(d.columnType(field))
+}
+
+func (postgresDialect) tableColumns(ctx context.Context, q sqlExecutor, table string) ([]columnInfo, error) {
+	schema, base := splitTableName(table)
+	schemaSQL, args := schemaPredicate(schema, "current_schema()")
+	args = append(append(append(args, base), args...), base)
+
+	rows, err := q.QueryContext(ctx, fmt.Sprintf("SELECT c.column_name, c.data_type, COALESCE(pk.ordinal_position, 0), c.column_default "+
+		"FROM information_schema.columns c "+
+		"LEFT JOIN (SELECT kcu.column_name, kcu.ordinal_position FROM information_schema.table_constraints tc "+
+		"JOIN information_schema.key_column_usage kcu ON tc.constraint_name = kcu.constraint_name AND tc.table_schema = kcu.table_schema "+
+		"WHERE tc.constraint_type = 'PRIMARY KEY' AND tc.table_schema = %[1]s AND tc.table_name = ?) pk ON pk.column_name = c.column_name "+
+		"WHERE c.table_schema = %[1]s AND c.table_name = ? ORDER BY c.ordinal_position;", schemaSQL), args...)
+	if err != nil {
+		return nil, fmt.Errorf("describe table %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	var columns []columnInfo
+	for rows.Next() {
+		var col columnInfo
+		if err := rows.Scan(&col.Name, &col.Type, &col.PrimaryKey, &col.Default); err != nil {
+			return nil, fmt.Errorf("scan table info %s: %w", table, err)
+		}
+		columns = append(columns, col)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate table info %s: %w", table, err)
+	}
+
+	return columns, nil
+}
+
+func (postgresDialect) crossSchemaForeignKeys() bool {
+	return true
+}
+
+func (d postgresDialect) indexTarget(table, index string) (string, string) {
+	return d.quoteIdent(index), quoteTable(d, table)
+}
+
+func (d postgresDialect) indexColumn(field RegisteredStructField) string {
+	return d.quoteIdent(field.Opts.KeyName)
+}
+
+func (postgresDialect) partialIndexes() bool {
+	return true
+}
+
+func (d postgresDialect) dropIndexSQL(table, index string) string {
+	// Indexes live in their table's schema.
+	schema, _ := splitTableName(table)
+	if schema != "" {
+		index = schema + "." + index
+	}
+	return fmt.Sprintf("DROP INDEX %s;", quoteTable(d, index))
+}
+
+func (postgresDialect) tableIndexes(ctx context.Context, q sqlExecutor, table string) ([]indexInfo, error) {
+	// Indexes backing constraints are left out. Predicates come back rewritten by the server,
+	// so only whether an index is partial is reported.
+	schema, base := splitTableName(table)
+	schemaSQL, args := schemaPredicate(schema, "current_schema()")
+
+	rows, err := q.QueryContext(ctx, fmt.Sprintf("SELECT i.relname, ix.indisunique, ix.indpred IS NOT NULL, a.attname "+
+		"FROM pg_catalog.pg_index ix "+
+		"JOIN pg_catalog.pg_class t ON t.oid = ix.indrelid "+
+		"JOIN pg_catalog.pg_class i ON i.oid = ix.indexrelid "+
+		"JOIN pg_catalog.pg_namespace n ON n.oid = t.relnamespace "+
+		"CROSS JOIN LATERAL unnest(ix.indkey::int2[]) WITH ORDINALITY AS k(attnum, position) "+
+		"JOIN pg_catalog.pg_attribute a ON a.attrelid = t.oid AND a.attnum = k.attnum "+
+		"WHERE n.nspname = %s AND t.relname = ? "+
+		"AND NOT EXISTS (SELECT 1 FROM pg_catalog.pg_constraint c WHERE c.conindid = ix.indexrelid) "+
+		"ORDER BY i.relname, k.position;", schemaSQL), append(args, base)...)
+	if err != nil {
+		return nil, fmt.Errorf("describe indexes %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	var indexes []indexInfo
+	for rows.Next() {
+		var (
+			info   indexInfo
+			column string
+		)
+
+		if err := rows.Scan(&info.Name, &info.Unique, &info.Partial, &column); err != nil {
+			return nil, fmt.Errorf("scan index info %s: %w", table, err)
+		}
+
+		if len(indexes) == 0 || indexes[len(indexes)-1].Name != info.Name {
+			indexes = append(indexes, info)
+		}
+		indexes[len(indexes)-1].Columns = append(indexes[len(indexes)-1].Columns, column)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate index info %s: %w", table, err)
+	}
+
+	return indexes, nil
+}
+
+func (postgresDialect) tableForeignKeys(ctx context.Context, q sqlExecutor, table string) (map[string]foreignKeyInfo, error) {
+	schema, base := splitTableName(table)
+	schemaSQL, args := schemaPredicate(schema, "current_schema()")
+
+	rows, err := q.QueryContext(ctx, fmt.Sprintf("SELECT tc.constraint_name, kcu.column_name, ccu.table_name, ccu.column_name, rc.delete_rule, rc.update_rule "+
+		"FROM information_schema.table_constraints tc "+
+		"JOIN information_schema.key_column_usage kcu ON tc.constraint_name = kcu.constraint_name AND tc.table_schema = kcu.table_schema "+
+		"JOIN information_schema.constraint_column_usage ccu ON tc.constraint_name = ccu.constraint_name AND tc.table_schema = ccu.table_schema "+
+		"JOIN information_schema.referential_constraints rc ON tc.constraint_name = rc.constraint_name AND tc.table_schema = rc.constraint_schema "+
+		"WHERE tc.constraint_type = 'FOREIGN KEY' AND tc.table_schema = %s AND tc.table_name = ?;", schemaSQL), append(args, base)...)
+	if err != nil {
+		return nil, fmt.Errorf("describe foreign keys %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	foreignKeys := make(map[string]foreignKeyInfo)
+	for rows.Next() {
+		var info foreignKeyInfo
+		if err := rows.Scan(&info.Name, &info.From, &info.Table, &info.To, &info.OnDelete, &info.OnUpdate); err != nil {
+			return nil, fmt.Errorf("scan foreign key info %s: %w", table, err)
+		}
+		foreignKeys[normalizeIdentifier(info.From)] = info
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate foreign key info %s: %w", table, err)
+	}
+
+	return foreignKeys, nil
+}
+
+func (d postgresDialect) renameColumnSQL(table, oldName, newName string) string {
+	return fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s;", quoteTable(d, table), d.quoteIdent(oldName), d.quoteIdent(newName))
+}
+
+func (d postgresDialect) alterColumnSQL(table string, field RegisteredStructField) []string {
+	var (
+		column   = d.quoteIdent(field.Opts.KeyName)
+		typeName = d.columnType(field)
+		nullable = "DROP NOT NULL"
+	)
+
+	if typeName == "BIGSERIAL" {
+		typeName = "BIGINT"
+	}
+
+	if field.Opts.NotNull {
+		nullable = "SET NOT NULL"
+	}
+
+	statements := []string{
+		fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE %s USING %s::%s;", quoteTable(d, table), column, typeName, column, typeName),
+	}
+
+	// Primary key columns are always NOT NULL.
+	if !field.Opts.PrimaryKey {
+		statements = append(statements, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s %s;", quoteTable(d, table), column, nullable))
+	}
+
+	// Serial columns keep their sequence default.
+	switch {
+	case field.Opts.AutoIncr:
+	case field.Opts.Default != "":
+		statements = append(statements, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET DEFAULT %s;", quoteTable(d, table), column, field.Opts.Default))
+	default:
+		statements = append(statements, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP DEFAULT;", quoteTable(d, table), column))
+	}
+
+	return statements
+}
+
+// dropPrimaryKeySQL looks up the name of the key constraint, which differs from the default
+// <table>_pkey for tables created elsewhere or renamed since.
+func (d postgresDialect) dropPrimaryKeySQL(ctx context.Context, q sqlExecutor, table string) (string, error) {
+	schema, base := splitTableName(table)
+	schemaSQL, args := schemaPredicate(schema, "current_schema()")
+
+	var name string
+	if err := q.QueryRowContext(ctx, fmt.Sprintf("SELECT tc.constraint_name FROM information_schema.table_constraints tc "+
+		"WHERE tc.constraint_type = 'PRIMARY KEY' AND tc.table_schema = %s AND tc.table_name = ?;", schemaSQL), append(args, base)...).Scan(&name); err != nil {
+		return "", fmt.Errorf("find primary key constraint of %s: %w", table, err)
+	}
+
+	return fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s;", quoteTable(d, table), d.quoteIdent(name)), nil
+}
+
+func (d postgresDialect) dropColumnSQL(table, column string) string {
+	return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;", quoteTable(d, table), d.quoteIdent(column))
+}
+
+func (d postgresDialect) dropForeignKeySQL(table string, info foreignKeyInfo) string {
+	return fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s;", quoteTable(d, table), d.quoteIdent(info.Name))
+}
+
+func (d postgresDialect) addForeignKeySQL(table string, field RegisteredStructField) string {
+	return fmt.Sprintf("ALTER TABLE %s ADD %s;", quoteTable(d, table), foreignKeyConstraint(d, field))
+}
+
+func (postgresDialect) transactionalDDL() bool {
+	return true
+}
+
+// jsonExtract converts a $.a.b[0] path to a {a,b,0} path array and extracts the value as text,
+// cast to numeric or boolean when it is compared with a number or bool.
+func (postgresDialect) jsonExtract(column, path string, kind jsonKind) string {
+	var keys []string
+	for _, part := range strings.Split(strings.TrimPrefix(path, "$"), ".") {
+		for part != "" {
+			key, rest, _ := strings.Cut(part, "[")
+			if key != "" {
+				keys = append(keys, key)
+			}
+			index, after, _ := strings.Cut(rest, "]")
+			if index != "" {
+				keys = append(keys, index)
+			}
+			part = after
+		}
+	}
+	extract := fmt.Sprintf("(%s::jsonb #>> %s)", column, quoteString("{"+strings.Join(keys, ",")+"}"))
+	switch kind {
+	case jsonNumber:
+		return extract + "::numeric"
+	case jsonBool:
+		return extract + "::boolean"
+	}
+	return extract
+}
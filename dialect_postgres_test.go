@@ -0,0 +1,383 @@
+package gomysql
+
+import (
+	"database/sql/driver"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+type PostgresTeam struct {
+	ID   int    `gomysql:"id,primary,increment"`
+	Name string `gomysql:"name,unique"`
+}
+
+type PostgresPlayer struct {
+	ID       int       `gomysql:"id,primary,increment"`
+	TeamID   int       `gomysql:"team_id,fkey:PostgresTeam.id"`
+	Nickname string    `gomysql:"nickname,notnull"`
+	Score    float64   `gomysql:"score"`
+	Joined   time.Time `gomysql:"joined"`
+	Tags     []string  `gomysql:"tags"`
+}
+
+func TestPostgresRebind(t *testing.T) {
+	cases := map[string]string{
+		"SELECT 1;": "SELECT 1;",
+		"UPDATE t SET a = ?, b = a + ? WHERE c = ? AND d IN (?, ?);": "UPDATE t SET a = $1, b = a + $2 WHERE c = $3 AND d IN ($4, $5);",
+		`SELECT '?' FROM "odd?name" WHERE x = ?;`:                    `SELECT '?' FROM "odd?name" WHERE x = $1;`,
+	}
+
+	for input, expected := range cases {
+		if got := Postgres.rebind(input); got != expected {
+			t.Fatalf("rebind(%q) = %q, want %q", input, got, expected)
+		}
+	}
+}
+
+func TestFilterBuildFor(t *testing.T) {
+	field := &RegisteredStructField{Opts: SQLTagOpts{KeyName: "score"}, Type: reflect.TypeFor[int](), InternalType: TypeRepInt}
+	filter := NewFilter().
+		KeyCmp(field, OpIn, []int{1, 2}).
+		Or().
+		KeyCmp(field, OpGreaterThan, 10)
+
+	for d, want := range map[Dialect]string{
+		SQLite:   `WHERE "score" IN (?, ?) OR "score" > ?`,
+		MySQL:    "WHERE `score` IN (?, ?) OR `score` > ?",
+		Postgres: `WHERE "score" IN ($1, $2) OR "score" > $3`,
+	} {
+		fragment, args, err := filter.BuildFor(d)
+		if err != nil || fragment != want || len(args) != 3 {
+			t.Fatalf("BuildFor(%s) = %q, %v (%v), want %q", d.Name(), fragment, args, err, want)
+		}
+	}
+
+	if _, _, err := filter.BuildFor(nil); err == nil {
+		t.Fatalf("expected BuildFor without a dialect to fail")
+	}
+}
+
+func TestPostgresCreateAndInsertSQL(t *testing.T) {
+	db, fake := openFakeDriver(t, Postgres)
+
+	if _, err := RegisterOn(db, PostgresTeam{}); err != nil {
+		t.Fatalf("failed to register team: %v", err)
+	}
+
+	players, err := RegisterOn(db, PostgresPlayer{})
+	if err != nil {
+		t.Fatalf("failed to register player: %v", err)
+	}
+
+	expected := []string{
+		`CREATE TABLE IF NOT EXISTS "PostgresTeam" ("id" BIGSERIAL PRIMARY KEY, "name" TEXT UNIQUE);`,
+		`CREATE TABLE IF NOT EXISTS "PostgresPlayer" ("id" BIGSERIAL PRIMARY KEY, "team_id" BIGINT REFERENCES "PostgresTeam"("id"), "nickname" TEXT NOT NULL, "score" DOUBLE PRECISION, "joined" TIMESTAMPTZ, "tags" BYTEA);`,
+	}
+
+	if got := fake.queries(); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("unexpected create statements:\n got: %q\nwant: %q", got, expected)
+	}
+
+	fake.respond("RETURNING", []string{"id"}, []driver.Value{int64(42)})
+	fake.reset()
+
+	player := &PostgresPlayer{TeamID: 1, Nickname: "ace", Joined: time.Now()}
+	if err := players.Insert(player); err != nil {
+		t.Fatalf("failed to insert player: %v", err)
+	}
+
+	expectedInsert := `INSERT INTO "PostgresPlayer" ("team_id", "nickname", "score", "joined", "tags") VALUES ($1, $2, $3, $4, $5) RETURNING "id";`
+	if got := fake.last().Query; got != expectedInsert {
+		t.Fatalf("unexpected insert statement:\n got: %s\nwant: %s", got, expectedInsert)
+	}
+
+	if _, ok := fake.last().Args[3].(time.Time); !ok {
+		t.Fatalf("expected time.Time argument for TIMESTAMPTZ column, got %T", fake.last().Args[3])
+	}
+
+	if player.ID != 42 {
+		t.Fatalf("expected RETURNING id to be assigned, got %d", player.ID)
+	}
+}
+
+func TestPostgresFilterPlaceholders(t *testing.T) {
+	db, fake := openFakeDriver(t, Postgres)
+
+	players, err := RegisterOn(db, PostgresPlayer{})
+	if err != nil {
+		t.Fatalf("failed to register player: %v", err)
+	}
+
+	filter := NewFilter().
+		KeyCmp(players.FieldByGoName("TeamID"), OpIn, []int{1, 2}).
+		And().
+		KeyCmp(players.FieldByGoName("Score"), OpGreaterThan, 10)
+
+	if _, err := players.UpdateWithFilter(filter, SetAdd(players.FieldByGoName("Score"), 1)); err != nil {
+		t.Fatalf("failed to update with filter: %v", err)
+	}
+
+	expected := `UPDATE "PostgresPlayer" SET "score" = "score" + $1 WHERE "team_id" IN ($2, $3) AND "score" > $4;`
+	if got := fake.last().Query; got != expected {
+		t.Fatalf("unexpected update statement:\n got: %s\nwant: %s", got, expected)
+	}
+}
+
+func TestPostgresMigrateAltersColumns(t *testing.T) {
+	db, fake := openFakeDriver(t, Postgres)
+
+	players, err := RegisterOn(db, PostgresPlayer{})
+	if err != nil {
+		t.Fatalf("failed to register player: %v", err)
+	}
+
+	fake.respond("information_schema.columns", []string{"column_name", "data_type", "ordinal_position", "column_default"},
+		[]driver.Value{"id", "bigint", int64(1), nil},
+		[]driver.Value{"team_id", "bigint", int64(0), nil},
+		[]driver.Value{"nickname", "text", int64(0), nil},
+		[]driver.Value{"score", "integer", int64(0), nil},
+		[]driver.Value{"joined", "timestamp with time zone", int64(0), nil},
+		[]driver.Value{"tags", "bytea", int64(0), nil},
+	)
+	fake.reset()
+
+	report, err := players.Migrate(MigrationOptions{AllowDestructive: true})
+	if err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+
+	if !report.Altered || len(report.ChangedColumns) != 2 {
+		t.Fatalf("expected score and team_id to be altered in place, got %+v", report)
+	}
+
+	expected := []string{
+		"BEGIN",
+		`ALTER TABLE "PostgresPlayer" ALTER COLUMN "score" TYPE DOUBLE PRECISION USING "score"::DOUBLE PRECISION;`,
+		`ALTER TABLE "PostgresPlayer" ALTER COLUMN "score" DROP NOT NULL;`,
+		`ALTER TABLE "PostgresPlayer" ALTER COLUMN "score" DROP DEFAULT;`,
+		`ALTER TABLE "PostgresPlayer" ALTER COLUMN "team_id" TYPE BIGINT USING "team_id"::BIGINT;`,
+		`ALTER TABLE "PostgresPlayer" ALTER COLUMN "team_id" DROP NOT NULL;`,
+		`ALTER TABLE "PostgresPlayer" ALTER COLUMN "team_id" DROP DEFAULT;`,
+		`ALTER TABLE "PostgresPlayer" ADD FOREIGN KEY ("team_id") REFERENCES "PostgresTeam"("id");`,
+		"COMMIT",
+	}
+
+	got := fake.queries()[3:]
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("unexpected migration statements:\n got: %q\nwant: %q", got, expected)
+	}
+}
+
+func TestPostgresMigrateDropsNamedPrimaryKey(t *testing.T) {
+	db, fake := openFakeDriver(t, Postgres)
+
+	teams, err := RegisterOn(db, PostgresTeam{})
+	if err != nil {
+		t.Fatalf("failed to register team: %v", err)
+	}
+
+	// The table was created elsewhere with its key on name, under a non-default constraint name.
+	fake.respond("information_schema.columns", []string{"column_name", "data_type", "ordinal_position", "column_default"},
+		[]driver.Value{"id", "bigint", int64(0), nil},
+		[]driver.Value{"name", "text", int64(1), nil},
+	)
+	fake.respond("SELECT tc.constraint_name FROM", []string{"constraint_name"}, []driver.Value{"legacy_teams_pk"})
+	fake.reset()
+
+	report, err := teams.Migrate(MigrationOptions{AllowDestructive: true})
+	if err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+
+	if !report.PrimaryKeyChanged || !report.Altered {
+		t.Fatalf("expected the primary key to be moved in place, got %+v", report)
+	}
+
+	lookup := slices.IndexFunc(fake.queries(), func(query string) bool { return strings.HasPrefix(query, "SELECT tc.constraint_name FROM") })
+	if lookup < 0 || fake.statements[lookup].Args[0] != "PostgresTeam" {
+		t.Fatalf("expected the key constraint to be looked up, got %q", fake.queries())
+	}
+
+	expected := []string{
+		"BEGIN",
+		`ALTER TABLE "PostgresTeam" DROP CONSTRAINT "legacy_teams_pk";`,
+		`ALTER TABLE "PostgresTeam" ADD PRIMARY KEY ("id");`,
+		"COMMIT",
+	}
+
+	if got := fake.queries()[lookup+1:]; !reflect.DeepEqual(got, expected) {
+		t.Fatalf("unexpected migration statements:\n got: %q\nwant: %q", got, expected)
+	}
+}
+
+type PostgresCounter struct {
+	ID    int    `gomysql:"id,primary,increment"`
+	Total uint64 `gomysql:"total"`
+}
+
+func TestPostgresRejectsUint64AboveBigint(t *testing.T) {
+	db, fake := openFakeDriver(t, Postgres)
+
+	counters, err := RegisterOn(db, PostgresCounter{})
+	if err != nil {
+		t.Fatalf("failed to register counter: %v", err)
+	}
+
+	if err := counters.Insert(&PostgresCounter{Total: math.MaxInt64}); err != nil {
+		t.Fatalf("failed to insert the largest BIGINT: %v", err)
+	}
+
+	fake.reset()
+	err = counters.Insert(&PostgresCounter{Total: math.MaxInt64 + 1})
+	if err == nil || !strings.Contains(err.Error(), "9223372036854775808 overflows PostgreSQL BIGINT") {
+		t.Fatalf("expected an overflow error, got %v", err)
+	}
+
+	if len(fake.queries()) != 0 {
+		t.Fatalf("expected nothing to reach the server, got %q", fake.queries())
+	}
+
+	_, err = counters.CountWithFilter(NewFilter().KeyCmp(counters.FieldByGoName("Total"), OpEqual, uint64(math.MaxUint64)))
+	if err == nil || !strings.Contains(err.Error(), "overflows PostgreSQL BIGINT") {
+		t.Fatalf("expected an overflow error from the filter, got %v", err)
+	}
+}
+
+func TestPostgresUpsertExistingRow(t *testing.T) {
+	db, fake := openFakeDriver(t, Postgres)
+
+	teams, err := RegisterOn(db, PostgresTeam{})
+	if err != nil {
+		t.Fatalf("failed to register team: %v", err)
+	}
+
+	fake.respond(`SELECT "id" FROM "PostgresTeam"`, []string{"id"}, []driver.Value{int64(9)})
+	fake.reset()
+
+	team := &PostgresTeam{Name: "blue"}
+	inserted, err := teams.Upsert(team, []*RegisteredStructField{teams.FieldByGoName("Name")})
+	if err != nil {
+		t.Fatalf("failed to upsert team: %v", err)
+	}
+
+	if inserted {
+		t.Fatalf("expected upsert of an existing row to report an update")
+	}
+
+	if team.ID != 9 {
+		t.Fatalf("expected the existing key to be assigned, got %d", team.ID)
+	}
+
+	expected := []string{
+		"BEGIN",
+		`SELECT "id" FROM "PostgresTeam" WHERE "name" = $1;`,
+		`INSERT INTO "PostgresTeam" ("name") VALUES ($1) ON CONFLICT ("name") DO NOTHING;`,
+		"COMMIT",
+	}
+	if got := fake.queries(); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("unexpected upsert statements:\n got: %q\nwant: %q", got, expected)
+	}
+}
+
+func TestPostgresInsertManyReturningKeys(t *testing.T) {
+	db, fake := openFakeDriver(t, Postgres)
+
+	teams, err := RegisterOn(db, PostgresTeam{})
+	if err != nil {
+		t.Fatalf("failed to register team: %v", err)
+	}
+
+	fake.respond("RETURNING", []string{"id"}, []driver.Value{int64(7)}, []driver.Value{int64(8)})
+	fake.reset()
+
+	items := []*PostgresTeam{{Name: "red"}, {Name: "blue"}}
+	if err := teams.InsertMany(items, InsertManyOptions{}); err != nil {
+		t.Fatalf("failed to insert teams: %v", err)
+	}
+
+	expected := []string{
+		"BEGIN",
+		"SAVEPOINT gomysql_sp_1;",
+		`INSERT INTO "PostgresTeam" ("name") VALUES ($1), ($2) RETURNING "id";`,
+		"RELEASE SAVEPOINT gomysql_sp_1;",
+		"COMMIT",
+	}
+	if got := fake.queries(); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("unexpected insert statements:\n got: %q\nwant: %q", got, expected)
+	}
+
+	if items[0].ID != 7 || items[1].ID != 8 {
+		t.Fatalf("expected RETURNING keys to be assigned in order, got %d and %d", items[0].ID, items[1].ID)
+	}
+}
+
+type PostgresArchivedTeam struct {
+	ID   int    `gomysql:"id,primary,increment"`
+	Name string `gomysql:"name,index"`
+}
+
+func (PostgresArchivedTeam) TableName() string {
+	return "archive.teams"
+}
+
+type PostgresArchivedPlayer struct {
+	ID     int `gomysql:"id,primary,increment"`
+	TeamID int `gomysql:"team_id,fkey:PostgresArchivedTeam.id"`
+}
+
+func TestPostgresSchemaQualifiedTable(t *testing.T) {
+	db, fake := openFakeDriver(t, Postgres)
+
+	teams, err := RegisterOn(db, PostgresArchivedTeam{})
+	if err != nil {
+		t.Fatalf("failed to register team: %v", err)
+	}
+
+	if _, err := RegisterOn(db, PostgresArchivedPlayer{}); err != nil {
+		t.Fatalf("failed to register player: %v", err)
+	}
+
+	queries := fake.queries()
+	expected := []string{
+		`CREATE TABLE IF NOT EXISTS "archive"."teams" ("id" BIGSERIAL PRIMARY KEY, "name" TEXT);`,
+		`CREATE TABLE IF NOT EXISTS "PostgresArchivedPlayer" ("id" BIGSERIAL PRIMARY KEY, "team_id" BIGINT REFERENCES "archive"."teams"("id"));`,
+	}
+	if queries[0] != expected[0] || queries[len(queries)-1] != expected[1] {
+		t.Fatalf("unexpected create statements:\n got: %q\nwant: %q", queries, expected)
+	}
+
+	fake.reset()
+	if _, err := teams.Migrate(MigrationOptions{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+
+	last := fake.last()
+	if want := `CREATE INDEX "teams_name_idx" ON "archive"."teams" ("name");`; last.Query != want {
+		t.Fatalf("unexpected index statement:\n got: %s\nwant: %s", last.Query, want)
+	}
+
+	describe := fake.statements[0]
+	if want := "tc.table_schema = $1 AND tc.table_name = $2"; !strings.Contains(describe.Query, want) ||
+		!reflect.DeepEqual(describe.Args, []driver.Value{"archive", "teams", "archive", "teams"}) {
+		t.Fatalf("expected schema-qualified introspection, got %s %v", describe.Query, describe.Args)
+	}
+}
+
+func TestForeignKeyTargetAmbiguous(t *testing.T) {
+	db, _ := openFakeDriver(t, Postgres)
+
+	db.registerTable("Team", "teams")
+	db.registerTable("Team", "archive.teams")
+
+	if _, err := db.resolveTable("Team"); err == nil {
+		t.Fatalf("expected two tables for one struct name to be ambiguous")
+	}
+
+	if table, err := db.resolveTable("Unregistered"); err != nil || table != "Unregistered" {
+		t.Fatalf("expected unregistered targets to be used as table names, got %s (%v)", table, err)
+	}
+}
@@ -0,0 +1,241 @@
+package gomysql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeDB is a database/sql driver that records every statement and answers queries from
+// canned responses, so dialects can be tested without a running server.
+type fakeDB struct {
+	mu         sync.Mutex
+	statements []fakeStatement
+	responses  []fakeResponse
+	lastID     int64
+	idStep     int64 // auto-increment step between inserts, 1 when zero
+}
+
+type fakeStatement struct {
+	Query string
+	Args  []driver.Value
+}
+
+type fakeResponse struct {
+	contains string
+	columns  []string
+	rows     [][]driver.Value
+}
+
+func openFakeDriver(t *testing.T, dialect Dialect) (*Driver, *fakeDB) {
+	t.Helper()
+
+	fake := &fakeDB{}
+	driver, err := OpenDB(sql.OpenDB(fake), DriverOptions{Dialect: dialect})
+	if err != nil {
+		t.Fatalf("failed to open fake %s driver: %v", dialect.Name(), err)
+	}
+
+	t.Cleanup(func() {
+		_ = driver.Close()
+	})
+
+	return driver, fake
+}
+
+// respond answers every query containing the given text with the rows.
+func (f *fakeDB) respond(contains string, columns []string, rows ...[]driver.Value) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.responses = append(f.responses, fakeResponse{contains: contains, columns: columns, rows: rows})
+}
+
+func (f *fakeDB) reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.statements = nil
+}
+
+func (f *fakeDB) queries() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var queries []string
+	for _, statement := range f.statements {
+		queries = append(queries, statement.Query)
+	}
+	return queries
+}
+
+func (f *fakeDB) last() fakeStatement {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.statements[len(f.statements)-1]
+}
+
+func (f *fakeDB) record(query string, args []driver.NamedValue) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	f.statements = append(f.statements, fakeStatement{Query: query, Args: values})
+}
+
+func (f *fakeDB) Connect(context.Context) (driver.Conn, error) {
+	return &fakeConn{db: f}, nil
+}
+
+func (f *fakeDB) Driver() driver.Driver {
+	return fakeDriver{db: f}
+}
+
+type fakeDriver struct {
+	db *fakeDB
+}
+
+func (d fakeDriver) Open(string) (driver.Conn, error) {
+	return &fakeConn{db: d.db}, nil
+}
+
+type fakeConn struct {
+	db *fakeDB
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{conn: c, query: query}, nil
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.db.record("BEGIN", nil)
+	return fakeTx{db: c.db}, nil
+}
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.db.record(query, args)
+
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	if strings.HasPrefix(query, "INSERT") {
+		c.db.lastID += max(c.db.idStep, 1)
+	}
+	return fakeResult{lastID: c.db.lastID}, nil
+}
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.db.record(query, args)
+
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	for _, response := range c.db.responses {
+		if strings.Contains(query, response.contains) {
+			return &fakeRows{columns: response.columns, rows: response.rows}, nil
+		}
+	}
+
+	return &fakeRows{columns: []string{"value"}}, nil
+}
+
+// CheckNamedValue accepts every value, converting driver.Valuers as database/sql would.
+func (c *fakeConn) CheckNamedValue(value *driver.NamedValue) error {
+	if valuer, ok := value.Value.(driver.Valuer); ok {
+		converted, err := valuer.Value()
+		if err != nil {
+			return err
+		}
+		value.Value = converted
+	}
+	return nil
+}
+
+// fakeStmt runs prepared statements through the connection, so they are recorded and
+// answered like direct queries.
+type fakeStmt struct {
+	conn  *fakeConn
+	query string
+}
+
+func (s *fakeStmt) Close() error {
+	return nil
+}
+
+func (s *fakeStmt) NumInput() int {
+	return -1
+}
+
+func (s *fakeStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, errors.New("fake driver requires ExecContext")
+}
+
+func (s *fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	return nil, errors.New("fake driver requires QueryContext")
+}
+
+func (s *fakeStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return s.conn.ExecContext(ctx, s.query, args)
+}
+
+func (s *fakeStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return s.conn.QueryContext(ctx, s.query, args)
+}
+
+type fakeTx struct {
+	db *fakeDB
+}
+
+func (t fakeTx) Commit() error {
+	t.db.record("COMMIT", nil)
+	return nil
+}
+
+func (t fakeTx) Rollback() error {
+	t.db.record("ROLLBACK", nil)
+	return nil
+}
+
+type fakeResult struct {
+	lastID int64
+}
+
+func (r fakeResult) LastInsertId() (int64, error) {
+	return r.lastID, nil
+}
+
+func (r fakeResult) RowsAffected() (int64, error) {
+	return 1, nil
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+	next    int
+}
+
+func (r *fakeRows) Columns() []string {
+	return r.columns
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.rows) {
+		return io.EOF
+	}
+
+	copy(dest, r.rows[r.next])
+	r.next++
+	return nil
+}
@@ -11,10 +11,14 @@ import (
 const (
 	stringSliceMagic = "GMS1"
 	timeMagic        = "GMT1"
+	uint64Magic      = "GMU1"
 	sqlTimeLayout    = "2006-01-02T15:04:05.000000000Z"
 )
 
-var timeType = reflect.TypeOf(time.Time{})
+var (
+	timeType  = reflect.TypeOf(time.Time{})
+	bytesType = reflect.TypeOf([]byte(nil))
+)
 
 func appendUvarint(dst []byte, v uint64) []byte {
 	var buf [binary.MaxVarintLen64]byte
@@ -140,3 +144,16 @@ func parseSQLTimeValue(raw string) (time.Time, error) {
 
 	return time.Time{}, fmt.Errorf("invalid time format %q", raw)
 }
+
+// encodeUint64 stores a uint64 above math.MaxInt64, which SQLite cannot hold as an integer, as
+// a marked big-endian blob. Blobs sort after every integer and among themselves by value.
+func encodeUint64(value uint64) []byte {
+	return binary.BigEndian.AppendUint64([]byte(uint64Magic), value)
+}
+
+func decodeUint64(raw []byte) (uint64, bool) {
+	if len(raw) != len(uint64Magic)+8 || string(raw[:len(uint64Magic)]) != uint64Magic {
+		return 0, false
+	}
+	return binary.BigEndian.Uint64(raw[len(uint64Magic):]), true
+}
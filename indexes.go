@@ -0,0 +1,360 @@
+package gomysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Index describes a secondary index on a registered struct's table.
+type Index struct {
+	Name    string
+	Columns []string // SQL column names, in index order
+	Unique  bool
+	Where   string // predicate of a partial index, empty for a full index
+}
+
+// Indexer is implemented by structs that declare indexes the index tag option cannot express,
+// such as multi-column unique or partial indexes. Its indexes are added to the tagged ones.
+type Indexer interface {
+	Indexes() []Index
+}
+
+type indexInfo struct {
+	Name    string
+	Columns []string
+	Unique  bool
+	Where   string // predicate as written, empty when the dialect cannot report it
+	Partial bool
+}
+
+// Indexes returns the secondary indexes declared by tags and the Indexer interface.
+func (r *RegisteredStruct[T]) Indexes() []Index {
+	indexes := make([]Index, len(r.indexes))
+	for i, index := range r.indexes {
+		index.Columns = slices.Clone(index.Columns)
+		indexes[i] = index
+	}
+	return indexes
+}
+
+// collectIndexes gathers the tagged indexes, grouped by name in field order, and those of an
+// Indexer, and validates them against the struct's columns and the dialect.
+func (r *RegisteredStruct[T]) collectIndexes() error {
+	var indexes []Index
+	for _, field := range r.Fields {
+		if !field.Opts.Indexed {
+			continue
+		}
+
+		name := field.Opts.IndexName
+		if name == "" {
+			_, table := splitTableName(r.Name)
+			name = fmt.Sprintf("%s_%s_idx", table, field.Opts.KeyName)
+		}
+
+		if i := slices.IndexFunc(indexes, func(index Index) bool { return index.Name == name }); i >= 0 {
+			indexes[i].Columns = append(indexes[i].Columns, field.Opts.KeyName)
+			continue
+		}
+
+		indexes = append(indexes, Index{Name: name, Columns: []string{field.Opts.KeyName}})
+	}
+
+	if indexer, ok := reflect.New(r.Type).Interface().(Indexer); ok {
+		for _, index := range indexer.Indexes() {
+			if slices.ContainsFunc(indexes, func(other Index) bool { return other.Name == index.Name }) {
+				return fmt.Errorf("duplicate index %s on struct %s", index.Name, r.Name)
+			}
+			index.Columns = slices.Clone(index.Columns)
+			indexes = append(indexes, index)
+		}
+	}
+
+	for _, index := range indexes {
+		if index.Name == "" || len(index.Columns) == 0 {
+			return fmt.Errorf("index on struct %s needs a name and columns", r.Name)
+		}
+
+		for _, column := range index.Columns {
+			if r.FieldBySQLName(column) == nil {
+				return fmt.Errorf("index %s references unknown column %s on struct %s", index.Name, column, r.Name)
+			}
+		}
+
+		if index.Where != "" && !r.dialect().partialIndexes() {
+			return fmt.Errorf("partial index %s: %w", index.Name, ErrUnsupportedByDialect)
+		}
+	}
+
+	r.indexes = indexes
+	return nil
+}
+
+func (r *RegisteredStruct[T]) createIndexSQL(index Index) string {
+	d := r.dialect()
+
+	columns := make([]string, len(index.Columns))
+	for i, column := range index.Columns {
+		columns[i] = d.indexColumn(*r.FieldBySQLName(column))
+	}
+
+	var unique string
+	if index.Unique {
+		unique = "UNIQUE "
+	}
+
+	var where string
+	if index.Where != "" {
+		where = " WHERE " + index.Where
+	}
+
+	name, on := d.indexTarget(r.Name, index.Name)
+	return fmt.Sprintf("CREATE %sINDEX %s ON %s (%s)%s;", unique, name, on, joinColumns(columns), where)
+}
+
+// createMissingIndexes creates the declared indexes that do not exist yet and whose columns
+// are all present, leaving the rest to Migrate.
+func (r *RegisteredStruct[T]) createMissingIndexes(ctx context.Context, q sqlExecutor) error {
+	d := r.dialect()
+
+	existing, err := d.tableIndexes(ctx, q, r.Name)
+	if err != nil {
+		return err
+	}
+
+	columns, err := d.tableColumns(ctx, q, r.Name)
+	if err != nil {
+		return err
+	}
+
+	present := make(map[string]bool, len(columns))
+	for _, col := range columns {
+		present[normalizeIdentifier(col.Name)] = true
+	}
+
+	for _, index := range r.indexes {
+		if slices.ContainsFunc(existing, func(info indexInfo) bool { return normalizeIdentifier(info.Name) == normalizeIdentifier(index.Name) }) {
+			continue
+		}
+
+		if !slices.ContainsFunc(index.Columns, func(column string) bool { return !present[normalizeIdentifier(column)] }) {
+			if _, err := q.ExecContext(ctx, r.createIndexSQL(index)); err != nil {
+				return fmt.Errorf("create index %s: %w", index.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// diffIndexes compares the declared indexes with the table's, with renamed columns under their
+// new names. An index whose definition changed is both dropped and added. An undeclared index
+// is dropped only when the index or unique tag of one of the struct's columns, under its current
+// or a previous name, would have generated it and the column is no longer indexed that way.
+// Others, such as indexes created by hand, are kept.
+func (r *RegisteredStruct[T]) diffIndexes(existing []indexInfo, renames map[string]string) (added []Index, dropped, kept []indexInfo) {
+	managedUnique := make(map[string]bool)
+	for _, field := range r.Fields {
+		if field.Opts.Unique {
+			managedUnique[normalizeIdentifier(uniqueIndexName(r.Name, field))] = true
+		}
+	}
+
+	existingByName := make(map[string]indexInfo, len(existing))
+	for _, info := range existing {
+		if managedUnique[normalizeIdentifier(info.Name)] {
+			continue
+		}
+
+		for i, column := range info.Columns {
+			if newName, ok := renames[column]; ok {
+				info.Columns[i] = newName
+			}
+		}
+		existingByName[normalizeIdentifier(info.Name)] = info
+	}
+
+	declared := make(map[string]bool, len(r.indexes))
+	for _, index := range r.indexes {
+		key := normalizeIdentifier(index.Name)
+		declared[key] = true
+
+		info, ok := existingByName[key]
+		if !ok {
+			added = append(added, index)
+			continue
+		}
+
+		if !indexMatches(info, index) {
+			dropped = append(dropped, info)
+			added = append(added, index)
+		}
+	}
+
+	generated := r.generatedIndexNames(renames)
+	for key, info := range existingByName {
+		column, ok := generated[key]
+		switch {
+		case declared[key]:
+		case ok && len(info.Columns) == 1 && normalizeIdentifier(info.Columns[0]) == column:
+			dropped = append(dropped, info)
+		default:
+			kept = append(kept, info)
+		}
+	}
+
+	byName := func(a, b indexInfo) int { return strings.Compare(a.Name, b.Name) }
+	slices.SortFunc(dropped, byName)
+	slices.SortFunc(kept, byName)
+	return added, dropped, kept
+}
+
+// generatedIndexNames maps the normalized names the index tag and, for columns that are no longer
+// unique, the unique tag generate for each column under its current and previous names, to the
+// column's normalized current name.
+func (r *RegisteredStruct[T]) generatedIndexNames(renames map[string]string) map[string]string {
+	_, table := splitTableName(r.Name)
+
+	generated := make(map[string]string)
+	for _, field := range r.Fields {
+		column := normalizeIdentifier(field.Opts.KeyName)
+		names := []string{field.Opts.KeyName}
+		for oldName, newName := range renames {
+			if normalizeIdentifier(newName) == column {
+				names = append(names, oldName)
+			}
+		}
+
+		for _, name := range names {
+			generated[normalizeIdentifier(fmt.Sprintf("%s_%s_idx", table, name))] = column
+			if !field.Opts.Unique {
+				generated[normalizeIdentifier(fmt.Sprintf("%s_%s_unique", table, name))] = column
+			}
+		}
+	}
+
+	return generated
+}
+
+// restorableIndex describes an undeclared index so it can be recreated after a table rebuild.
+// It fails for indexes on expressions or on columns the struct no longer has.
+func (r *RegisteredStruct[T]) restorableIndex(info indexInfo) (Index, bool) {
+	if info.Partial && info.Where == "" {
+		return Index{}, false
+	}
+
+	for _, column := range info.Columns {
+		if r.FieldBySQLName(column) == nil {
+			return Index{}, false
+		}
+	}
+
+	return Index{Name: info.Name, Columns: info.Columns, Unique: info.Unique, Where: info.Where}, true
+}
+
+func indexMatches(info indexInfo, index Index) bool {
+	if info.Unique != index.Unique || info.Partial != (index.Where != "") ||
+		!slices.EqualFunc(info.Columns, index.Columns, func(a, b string) bool { return normalizeIdentifier(a) == normalizeIdentifier(b) }) {
+		return false
+	}
+
+	return info.Where == "" || strings.Join(strings.Fields(info.Where), " ") == strings.Join(strings.Fields(index.Where), " ")
+}
+
+func uniqueIndexName(table string, field RegisteredStructField) string {
+	_, table = splitTableName(table)
+	return fmt.Sprintf("%s_%s_unique", table, field.Opts.KeyName)
+}
+
+func tableIndexes(ctx context.Context, q sqlExecutor, table string) ([]indexInfo, error) {
+	rows, err := q.QueryContext(ctx, sqlitePragma("index_list", table))
+	if err != nil {
+		return nil, fmt.Errorf("describe indexes %s: %w", table, err)
+	}
+
+	var indexes []indexInfo
+	for rows.Next() {
+		var (
+			seq     int
+			info    indexInfo
+			origin  string
+			partial int
+		)
+
+		if err := rows.Scan(&seq, &info.Name, &info.Unique, &origin, &partial); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan index list %s: %w", table, err)
+		}
+
+		// Only indexes made by CREATE INDEX; "u" and "pk" back table constraints.
+		if origin == "c" {
+			info.Partial = partial != 0
+			indexes = append(indexes, info)
+		}
+	}
+
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return nil, fmt.Errorf("iterate index list %s: %w", table, err)
+	}
+
+	// The pool may hold a single connection, so the index list is read before each index.
+	schema, _ := splitTableName(table)
+	master, _ := sqliteMaster(table)
+	for i := range indexes {
+		index := indexes[i].Name
+		if schema != "" {
+			index = schema + "." + index
+		}
+
+		if indexes[i].Columns, err = indexColumns(ctx, q, index); err != nil {
+			return nil, err
+		}
+
+		if indexes[i].Partial {
+			var createSQL string
+			if err := q.QueryRowContext(ctx, fmt.Sprintf("SELECT sql FROM %s WHERE type = 'index' AND name = ?;", master), indexes[i].Name).Scan(&createSQL); err != nil {
+				return nil, fmt.Errorf("describe index %s: %w", indexes[i].Name, err)
+			}
+
+			if idx := strings.LastIndex(strings.ToUpper(createSQL), " WHERE "); idx >= 0 {
+				indexes[i].Where = strings.TrimSuffix(strings.TrimSpace(createSQL[idx+len(" WHERE "):]), ";")
+			}
+		}
+	}
+
+	return indexes, nil
+}
+
+func indexColumns(ctx context.Context, q sqlExecutor, index string) ([]string, error) {
+	rows, err := q.QueryContext(ctx, sqlitePragma("index_info", index))
+	if err != nil {
+		return nil, fmt.Errorf("describe index %s: %w", index, err)
+	}
+	defer rows.Close()
+
+	var columns []string
+	for rows.Next() {
+		var (
+			seqno int
+			cid   int
+			name  sql.NullString // NULL for expression columns
+		)
+
+		if err := rows.Scan(&seqno, &cid, &name); err != nil {
+			return nil, fmt.Errorf("scan index info %s: %w", index, err)
+		}
+		columns = append(columns, name.String)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate index info %s: %w", index, err)
+	}
+
+	return columns, nil
+}
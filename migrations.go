@@ -1,9 +1,12 @@
 package gomysql
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
+	"reflect"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -25,18 +28,30 @@ type MigrationReport struct {
 	DroppedColumns []string
 	ChangedColumns []string
 	RenamedColumns map[string]string // old column name -> new column name
+	// PrimaryKeyChanged is set when the key columns differ from the table's primary key.
+	PrimaryKeyChanged bool
+	// AddedIndexes and DroppedIndexes name the secondary indexes created and removed; an index
+	// whose definition changed appears in both.
+	AddedIndexes   []string
+	DroppedIndexes []string
 	Rebuilt        bool
+	Altered        bool
 }
 
 type columnInfo struct {
-	Name string
-	Type string
+	Name       string
+	Type       string
+	PrimaryKey int // 1-based position in the primary key, 0 if not part of it
+	Default    sql.NullString
 }
 
 type foreignKeyInfo struct {
-	From  string
-	Table string
-	To    string
+	Name     string
+	From     string
+	Table    string
+	To       string
+	OnDelete string
+	OnUpdate string
 }
 
 type copyColumnMapping struct {
@@ -60,11 +75,103 @@ func foreignKeyRefsEqual(info *foreignKeyInfo, ref *ForeignKeyRef) bool {
 	case info == nil || ref == nil:
 		return false
 	default:
-		return normalizeIdentifier(info.Table) == normalizeIdentifier(ref.TableName) &&
-			normalizeIdentifier(info.To) == normalizeIdentifier(ref.ColumnName)
+		// Introspection reports the referenced table without its schema.
+		_, table := splitTableName(ref.TableName)
+		return normalizeIdentifier(info.Table) == normalizeIdentifier(table) &&
+			normalizeIdentifier(info.To) == normalizeIdentifier(ref.ColumnName) &&
+			foreignKeyActionsEqual(info.OnDelete, ref.OnDelete) &&
+			foreignKeyActionsEqual(info.OnUpdate, ref.OnUpdate)
 	}
 }
 
+// foreignKeyActionsEqual compares referential actions, treating an unspecified action as the
+// NO ACTION default.
+func foreignKeyActionsEqual(existing, desired string) bool {
+	normalize := func(action string) string {
+		if action, ok := parseForeignKeyAction(action); ok {
+			return action
+		}
+		return "NO ACTION"
+	}
+	return normalize(existing) == normalize(desired)
+}
+
+// normalizeDefault reduces a column default as reported by the database to a comparable form:
+// casts, wrapping parentheses and quotes are removed, and NULL is treated as no default.
+func normalizeDefault(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	for {
+		if idx := strings.LastIndex(value, "::"); idx > strings.LastIndex(value, "'") {
+			value = strings.TrimSpace(value[:idx])
+			continue
+		}
+
+		if strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")") && enclosedBy(value, '(', ')') {
+			value = strings.TrimSpace(value[1 : len(value)-1])
+			continue
+		}
+
+		break
+	}
+
+	if len(value) >= 2 && strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'") {
+		return strings.ReplaceAll(value[1:len(value)-1], "''", "'"), true
+	}
+
+	if strings.EqualFold(value, "NULL") {
+		return "", false
+	}
+
+	return strings.TrimSuffix(value, "()"), false
+}
+
+// enclosedBy reports whether the opening delimiter at the start of value is closed by the last byte.
+func enclosedBy(value string, open, close byte) bool {
+	depth := 0
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case open:
+			depth++
+		case close:
+			depth--
+			if depth == 0 {
+				return i == len(value)-1
+			}
+		}
+	}
+	return false
+}
+
+func defaultsMatch(existing sql.NullString, field RegisteredStructField) bool {
+	// Auto-increment columns carry a database-generated default such as a sequence.
+	if field.Opts.AutoIncr {
+		return true
+	}
+
+	var current string
+	if existing.Valid {
+		current = existing.String
+	}
+
+	have, haveQuoted := normalizeDefault(current)
+	want, wantQuoted := normalizeDefault(field.Opts.Default)
+	if haveQuoted || wantQuoted {
+		// MySQL reports string literals without their quotes, so only an empty string needs
+		// both sides quoted to tell it apart from no default.
+		return have == want && (have != "" || haveQuoted == wantQuoted)
+	}
+	return strings.EqualFold(have, want)
+}
+
+func checksMatch(checks map[string]string, key string, field RegisteredStructField) bool {
+	// A nil map means the dialect cannot introspect CHECK constraints.
+	if checks == nil {
+		return true
+	}
+
+	return strings.Join(strings.Fields(checks[key]), " ") == strings.Join(strings.Fields(field.Opts.Check), " ")
+}
+
 func lookupForeignKeyRef(foreignKeys map[string]foreignKeyInfo, key string) *foreignKeyInfo {
 	info, ok := foreignKeys[key]
 	if !ok {
@@ -74,10 +181,38 @@ func lookupForeignKeyRef(foreignKeys map[string]foreignKeyInfo, key string) *for
 	return &info
 }
 
+func hasForeignKey(foreignKeys map[string]foreignKeyInfo, key string) bool {
+	_, ok := foreignKeys[key]
+	return ok
+}
+
 func needsLegacyTimeMigration(srcType string, field RegisteredStructField) bool {
 	return field.InternalType == TypeRepTime && normalizeSQLType(srcType) == normalizeSQLType(typeNameString(TypeRepStructBlob))
 }
 
+// needsJSONMigration reports whether a gob blob column is now stored as JSON.
+func needsJSONMigration(srcType string, field RegisteredStructField) bool {
+	return field.InternalType == TypeRepJSON && normalizeSQLType(srcType) == normalizeSQLType(typeNameString(TypeRepStructBlob))
+}
+
+// migrateBlobToJSON re-encodes a field's gob blobs as JSON.
+func migrateBlobToJSON(field RegisteredStructField) func(any) (any, error) {
+	blob := field
+	blob.InternalType, _ = resolveInternalType(field.Type)
+
+	return func(raw any) (any, error) {
+		if raw == nil {
+			return nil, nil
+		}
+
+		value, err := decodeSQLValue(blob, raw)
+		if err != nil {
+			return nil, err
+		}
+		return getSQLValueOf(field, reflect.ValueOf(value))
+	}
+}
+
 func migrateLegacyTimeValue(raw any) (any, error) {
 	if raw == nil {
 		return nil, nil
@@ -109,8 +244,25 @@ func migrateLegacyTimeValue(raw any) (any, error) {
 	}
 }
 
-func (d *Driver) tableColumns(table string) ([]columnInfo, error) {
-	rows, err := d.db.Query(fmt.Sprintf("PRAGMA table_info(%s);", table))
+// sqlitePragma renders a pragma on a table, moving an attached-database prefix in front of
+// the pragma name as SQLite requires.
+func sqlitePragma(pragma, table string) string {
+	if schema, base := splitTableName(table); schema != "" {
+		return fmt.Sprintf("PRAGMA %s.%s(%s);", SQLite.quoteIdent(schema), pragma, SQLite.quoteIdent(base))
+	}
+	return fmt.Sprintf("PRAGMA %s(%s);", pragma, SQLite.quoteIdent(table))
+}
+
+// sqliteMaster returns the schema table of the database holding table, and its bare name.
+func sqliteMaster(table string) (string, string) {
+	if schema, base := splitTableName(table); schema != "" {
+		return SQLite.quoteIdent(schema) + ".sqlite_master", base
+	}
+	return "sqlite_master", table
+}
+
+func tableColumns(ctx context.Context, q sqlExecutor, table string) ([]columnInfo, error) {
+	rows, err := q.QueryContext(ctx, sqlitePragma("table_info", table))
 	if err != nil {
 		return nil, fmt.Errorf("describe table %s: %w", table, err)
 	}
@@ -132,8 +284,10 @@ func (d *Driver) tableColumns(table string) ([]columnInfo, error) {
 		}
 
 		columns = append(columns, columnInfo{
-			Name: name,
-			Type: sqlType,
+			Name:       name,
+			Type:       sqlType,
+			PrimaryKey: pk,
+			Default:    dflt,
 		})
 	}
 
@@ -144,8 +298,8 @@ func (d *Driver) tableColumns(table string) ([]columnInfo, error) {
 	return columns, nil
 }
 
-func (d *Driver) tableForeignKeys(table string) (map[string]foreignKeyInfo, error) {
-	rows, err := d.db.Query(fmt.Sprintf("PRAGMA foreign_key_list(%s);", table))
+func tableForeignKeys(ctx context.Context, q sqlExecutor, table string) (map[string]foreignKeyInfo, error) {
+	rows, err := q.QueryContext(ctx, sqlitePragma("foreign_key_list", table))
 	if err != nil {
 		return nil, fmt.Errorf("describe foreign keys %s: %w", table, err)
 	}
@@ -174,9 +328,11 @@ func (d *Driver) tableForeignKeys(table string) (map[string]foreignKeyInfo, erro
 		}
 
 		foreignKeys[normalizeIdentifier(from)] = foreignKeyInfo{
-			From:  from,
-			Table: refTable,
-			To:    to,
+			From:     from,
+			Table:    refTable,
+			To:       to,
+			OnDelete: onDelete,
+			OnUpdate: onUpdate,
 		}
 	}
 
@@ -187,38 +343,162 @@ func (d *Driver) tableForeignKeys(table string) (map[string]foreignKeyInfo, erro
 	return foreignKeys, nil
 }
 
+// tableChecks reads column CHECK constraints from the table's CREATE TABLE statement, keyed by
+// normalized column name.
+func tableChecks(ctx context.Context, q sqlExecutor, table string) (map[string]string, error) {
+	var createSQL sql.NullString
+	master, base := sqliteMaster(table)
+	err := q.QueryRowContext(ctx, fmt.Sprintf("SELECT sql FROM %s WHERE type = 'table' AND name = ?;", master), base).Scan(&createSQL)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("describe checks %s: %w", table, err)
+	}
+
+	checks := make(map[string]string)
+	start, end := strings.Index(createSQL.String, "("), strings.LastIndex(createSQL.String, ")")
+	if start < 0 || end < start {
+		return checks, nil
+	}
+
+	for _, definition := range splitTagOptions(createSQL.String[start+1 : end]) {
+		name, rest := splitColumnName(strings.TrimSpace(definition))
+		switch strings.ToUpper(name) {
+		case "", "CONSTRAINT", "PRIMARY", "UNIQUE", "CHECK", "FOREIGN":
+			continue
+		}
+
+		if check := extractCheck(rest); check != "" {
+			checks[normalizeIdentifier(name)] = check
+		}
+	}
+
+	return checks, nil
+}
+
+// splitColumnName splits a column definition into its unquoted name and the remainder.
+func splitColumnName(definition string) (string, string) {
+	if definition == "" {
+		return "", ""
+	}
+
+	if quote := definition[0]; quote == '"' || quote == '`' || quote == '[' {
+		if quote == '[' {
+			quote = ']'
+		}
+		if end := strings.IndexByte(definition[1:], quote); end >= 0 {
+			return definition[1 : end+1], definition[end+2:]
+		}
+		return "", ""
+	}
+
+	name, rest, _ := strings.Cut(definition, " ")
+	return name, rest
+}
+
+// extractCheck returns the expression of the first CHECK (...) clause outside quotes.
+func extractCheck(definition string) string {
+	var quote byte
+	for i := 0; i < len(definition); i++ {
+		c := definition[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+			continue
+		case c == '\'' || c == '"':
+			quote = c
+			continue
+		}
+
+		if i+5 > len(definition) || !strings.EqualFold(definition[i:i+5], "CHECK") || (i > 0 && isIdentByte(definition[i-1])) {
+			continue
+		}
+
+		rest := strings.TrimLeft(definition[i+5:], " \t\n")
+		if !strings.HasPrefix(rest, "(") {
+			continue
+		}
+
+		depth := 0
+		quote = 0
+		for j := 0; j < len(rest); j++ {
+			switch c := rest[j]; {
+			case quote != 0:
+				if c == quote {
+					quote = 0
+				}
+			case c == '\'' || c == '"':
+				quote = c
+			case c == '(':
+				depth++
+			case c == ')':
+				if depth--; depth == 0 {
+					return strings.TrimSpace(rest[1:j])
+				}
+			}
+		}
+		return ""
+	}
+	return ""
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
 func (r *RegisteredStruct[T]) Migrate(opts MigrationOptions) (*MigrationReport, error) {
+	return r.MigrateCtx(context.Background(), opts)
+}
+
+func (r *RegisteredStruct[T]) MigrateCtx(ctx context.Context, opts MigrationOptions) (*MigrationReport, error) {
 	if r.db == nil {
 		return nil, ErrDatabaseNotInitialized
 	}
 
-	r.db.lock.Lock()
-	defer r.db.lock.Unlock()
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	defer r.invalidateStatements()
 
 	report := &MigrationReport{
 		Table:          r.Name,
 		RenamedColumns: make(map[string]string),
 	}
 
-	existingColumns, err := r.db.tableColumns(r.Name)
+	d := r.dialect()
+	existingColumns, err := d.tableColumns(ctx, r.directExecutor(), r.Name)
 	if err != nil {
 		return report, err
 	}
 
-	existingForeignKeys, err := r.db.tableForeignKeys(r.Name)
+	existingForeignKeys, err := d.tableForeignKeys(ctx, r.directExecutor(), r.Name)
 	if err != nil {
 		return report, err
 	}
 
+	var existingChecks map[string]string
+	if checker, ok := d.(checkDialect); ok && len(existingColumns) > 0 {
+		if existingChecks, err = checker.tableChecks(ctx, r.directExecutor(), r.Name); err != nil {
+			return report, err
+		}
+	}
+
 	if len(existingColumns) == 0 {
-		if _, err := r.db.db.Exec(r.createTableSQL); err != nil {
+		if _, err := r.directExecutor().ExecContext(ctx, r.createTableSQL); err != nil {
 			return report, fmt.Errorf("create table %s: %w", r.Name, err)
 		}
 		for _, field := range r.Fields {
 			report.AddedColumns = append(report.AddedColumns, field.Opts.KeyName)
 		}
 		sort.Strings(report.AddedColumns)
-		return report, nil
+		return report, r.applyIndexes(ctx, report, nil, r.indexes)
+	}
+
+	existingIndexes, err := d.tableIndexes(ctx, r.directExecutor(), r.Name)
+	if err != nil {
+		return report, err
 	}
 
 	existingByKey := make(map[string]columnInfo, len(existingColumns))
@@ -254,7 +534,9 @@ func (r *RegisteredStruct[T]) Migrate(opts MigrationOptions) (*MigrationReport,
 			report.RenamedColumns[oldCol.Name] = keyName
 			usedExisting[oldKey] = true
 
-			if normalizeSQLType(oldCol.Type) != normalizeSQLType(typeNameString(field.InternalType)) ||
+			if !d.columnTypeMatches(oldCol.Type, field) ||
+				!defaultsMatch(oldCol.Default, field) ||
+				!checksMatch(existingChecks, oldKey, field) ||
 				!foreignKeyRefsEqual(lookupForeignKeyRef(existingForeignKeys, oldKey), field.Opts.ForeignKey) {
 				report.ChangedColumns = append(report.ChangedColumns, keyName)
 			}
@@ -263,7 +545,9 @@ func (r *RegisteredStruct[T]) Migrate(opts MigrationOptions) (*MigrationReport,
 
 		if col, ok := existingByKey[key]; ok {
 			usedExisting[key] = true
-			if normalizeSQLType(col.Type) != normalizeSQLType(typeNameString(field.InternalType)) ||
+			if !d.columnTypeMatches(col.Type, field) ||
+				!defaultsMatch(col.Default, field) ||
+				!checksMatch(existingChecks, key, field) ||
 				!foreignKeyRefsEqual(lookupForeignKeyRef(existingForeignKeys, key), field.Opts.ForeignKey) {
 				report.ChangedColumns = append(report.ChangedColumns, keyName)
 			}
@@ -284,48 +568,231 @@ func (r *RegisteredStruct[T]) Migrate(opts MigrationOptions) (*MigrationReport,
 	sort.Strings(report.DroppedColumns)
 	sort.Strings(report.ChangedColumns)
 
-	needsRebuild := len(report.DroppedColumns) > 0 || len(report.ChangedColumns) > 0 || len(report.RenamedColumns) > 0
-	if needsRebuild && !opts.AllowDestructive {
-		return report, fmt.Errorf("%w: columns=%v drops=%v renames=%v", ErrMigrationDestructive, report.ChangedColumns, report.DroppedColumns, report.RenamedColumns)
+	existingKey := existingPrimaryKey(existingColumns, report.RenamedColumns)
+	report.PrimaryKeyChanged = !slices.Equal(existingKey, r.primaryKeyNames())
+
+	needsRebuild := len(report.DroppedColumns) > 0 || len(report.ChangedColumns) > 0 || len(report.RenamedColumns) > 0 || report.PrimaryKeyChanged
+	_, alters := d.(alterDialect)
+
+	addedIndexes, droppedIndexes, keptIndexes := r.diffIndexes(existingIndexes, report.RenamedColumns)
+
+	// A rebuilt table starts without indexes; kept indexes that cannot be recreated are lost.
+	var restoredIndexes []Index
+	if needsRebuild && !alters {
+		for _, info := range keptIndexes {
+			if index, ok := r.restorableIndex(info); ok {
+				restoredIndexes = append(restoredIndexes, index)
+			} else {
+				droppedIndexes = append(droppedIndexes, info)
+			}
+		}
+	}
+
+	for _, info := range droppedIndexes {
+		report.DroppedIndexes = append(report.DroppedIndexes, info.Name)
+	}
+	sort.Strings(report.DroppedIndexes)
+	if (needsRebuild || len(droppedIndexes) > 0) && !opts.AllowDestructive {
+		return report, fmt.Errorf("%w: columns=%v drops=%v renames=%v primary key changed=%v dropped indexes=%v", ErrMigrationDestructive, report.ChangedColumns, report.DroppedColumns, report.RenamedColumns, report.PrimaryKeyChanged, report.DroppedIndexes)
 	}
 
 	for _, name := range report.AddedColumns {
 		field := desiredByKey[normalizeIdentifier(name)]
-		if field.Opts.NotNull {
+		if field.Opts.NotNull && field.Opts.Default == "" {
 			return report, fmt.Errorf("%w: column %s", ErrMigrationNotNull, name)
 		}
 	}
 
+	if alter, ok := d.(alterDialect); ok {
+		if err := r.applyIndexes(ctx, report, droppedIndexes, nil); err != nil {
+			return report, err
+		}
+		if err := r.alterTable(ctx, alter, report, len(existingKey) > 0, existingForeignKeys, desiredByKey); err != nil {
+			return report, err
+		}
+		report.Altered = true
+		return report, r.applyIndexes(ctx, report, nil, addedIndexes)
+	}
+
 	if needsRebuild {
-		if err := r.rebuildTable(existingByKey, renameNewToOld); err != nil {
+		if err := r.rebuildTable(ctx, existingByKey, renameNewToOld); err != nil {
 			return report, err
 		}
 		report.Rebuilt = true
+
+		// The rebuilt table starts without indexes, so every declared and kept one is recreated.
+		for _, index := range addedIndexes {
+			report.AddedIndexes = append(report.AddedIndexes, index.Name)
+		}
+		for _, index := range append(slices.Clone(r.indexes), restoredIndexes...) {
+			if _, err := r.directExecutor().ExecContext(ctx, r.createIndexSQL(index)); err != nil {
+				return report, fmt.Errorf("create index %s: %w", index.Name, err)
+			}
+		}
+		sort.Strings(report.AddedIndexes)
 		return report, nil
 	}
 
+	if err := r.applyIndexes(ctx, report, droppedIndexes, nil); err != nil {
+		return report, err
+	}
+
 	for _, name := range report.AddedColumns {
 		field := desiredByKey[normalizeIdentifier(name)]
-		columnSQL := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;", r.Name, columnDefinition(field, false))
-		if _, err := r.db.db.Exec(columnSQL); err != nil {
+		columnSQL := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;", r.quotedName(), columnDefinition(d, field, false))
+		if _, err := r.directExecutor().ExecContext(ctx, columnSQL); err != nil {
 			return report, fmt.Errorf("add column %s: %w", field.Opts.KeyName, err)
 		}
 
 		if field.Opts.Unique {
-			indexName := fmt.Sprintf("%s_%s_unique", r.Name, field.Opts.KeyName)
-			indexSQL := fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS %s ON %s(%s);", indexName, r.Name, field.Opts.KeyName)
-			if _, err := r.db.db.Exec(indexSQL); err != nil {
+			indexName := uniqueIndexName(r.Name, field)
+			name, on := d.indexTarget(r.Name, indexName)
+			indexSQL := fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS %s ON %s(%s);", name, on, r.quotedColumn(field))
+			if _, err := r.directExecutor().ExecContext(ctx, indexSQL); err != nil {
 				return report, fmt.Errorf("add unique index %s: %w", indexName, err)
 			}
 		}
 	}
 
-	return report, nil
+	return report, r.applyIndexes(ctx, report, nil, addedIndexes)
+}
+
+// applyIndexes drops and creates secondary indexes, recording them in the report.
+func (r *RegisteredStruct[T]) applyIndexes(ctx context.Context, report *MigrationReport, dropped []indexInfo, added []Index) error {
+	for _, info := range dropped {
+		if _, err := r.directExecutor().ExecContext(ctx, r.dialect().dropIndexSQL(r.Name, info.Name)); err != nil {
+			return fmt.Errorf("drop index %s: %w", info.Name, err)
+		}
+	}
+
+	for _, index := range added {
+		if _, err := r.directExecutor().ExecContext(ctx, r.createIndexSQL(index)); err != nil {
+			return fmt.Errorf("create index %s: %w", index.Name, err)
+		}
+		report.AddedIndexes = append(report.AddedIndexes, index.Name)
+	}
+
+	sort.Strings(report.AddedIndexes)
+	return nil
 }
 
-func (r *RegisteredStruct[T]) rebuildTable(existingByKey map[string]columnInfo, renameNewToOld map[string]string) error {
+// existingPrimaryKey lists the normalized key columns of a table in key order, with renamed
+// columns under their new names.
+func existingPrimaryKey(columns []columnInfo, renames map[string]string) []string {
+	var keyColumns []columnInfo
+	for _, col := range columns {
+		if col.PrimaryKey > 0 {
+			keyColumns = append(keyColumns, col)
+		}
+	}
+
+	sort.Slice(keyColumns, func(i, j int) bool {
+		return keyColumns[i].PrimaryKey < keyColumns[j].PrimaryKey
+	})
+
+	names := make([]string, 0, len(keyColumns))
+	for _, col := range keyColumns {
+		name := col.Name
+		if newName, ok := renames[col.Name]; ok {
+			name = newName
+		}
+		names = append(names, normalizeIdentifier(name))
+	}
+
+	return names
+}
+
+func (r *RegisteredStruct[T]) primaryKeyNames() []string {
+	names := make([]string, 0, len(r.PrimaryKeyFields))
+	for _, field := range r.PrimaryKeyFields {
+		names = append(names, normalizeIdentifier(field.Opts.KeyName))
+	}
+	return names
+}
+
+func (r *RegisteredStruct[T]) alterTable(ctx context.Context, alter alterDialect, report *MigrationReport, hasPrimaryKey bool, existingForeignKeys map[string]foreignKeyInfo, desiredByKey map[string]RegisteredStructField) error {
+	var statements []string
+
+	oldNames := make([]string, 0, len(report.RenamedColumns))
+	for oldName := range report.RenamedColumns {
+		oldNames = append(oldNames, oldName)
+	}
+	sort.Strings(oldNames)
+
+	for _, oldName := range oldNames {
+		statements = append(statements, alter.renameColumnSQL(r.Name, oldName, report.RenamedColumns[oldName]))
+	}
+
+	if report.PrimaryKeyChanged && hasPrimaryKey {
+		dropKey, err := alter.dropPrimaryKeySQL(ctx, r.directExecutor(), r.Name)
+		if err != nil {
+			return err
+		}
+		statements = append(statements, dropKey)
+	}
+
+	for _, name := range report.DroppedColumns {
+		if info, ok := existingForeignKeys[normalizeIdentifier(name)]; ok {
+			statements = append(statements, alter.dropForeignKeySQL(r.Name, info))
+		}
+		statements = append(statements, alter.dropColumnSQL(r.Name, name))
+	}
+
+	for _, name := range report.ChangedColumns {
+		field := desiredByKey[normalizeIdentifier(name)]
+		key := normalizeIdentifier(name)
+		for _, oldName := range oldNames {
+			if report.RenamedColumns[oldName] == name {
+				key = normalizeIdentifier(oldName)
+			}
+		}
+
+		fkChanged := !foreignKeyRefsEqual(lookupForeignKeyRef(existingForeignKeys, key), field.Opts.ForeignKey)
+		if fkChanged && hasForeignKey(existingForeignKeys, key) {
+			statements = append(statements, alter.dropForeignKeySQL(r.Name, existingForeignKeys[key]))
+		}
+
+		statements = append(statements, alter.alterColumnSQL(r.Name, field)...)
+
+		if fkChanged && field.Opts.HasForeignKey() {
+			statements = append(statements, alter.addForeignKeySQL(r.Name, field))
+		}
+	}
+
+	for _, name := range report.AddedColumns {
+		field := desiredByKey[normalizeIdentifier(name)]
+		statements = append(statements, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;", r.quotedName(), columnDefinition(r.dialect(), field, false)))
+		if field.Opts.HasForeignKey() && !r.dialect().inlineForeignKeys() {
+			statements = append(statements, alter.addForeignKeySQL(r.Name, field))
+		}
+	}
+
+	if report.PrimaryKeyChanged {
+		statements = append(statements, fmt.Sprintf("ALTER TABLE %s ADD PRIMARY KEY (%s);", r.quotedName(), r.quotedKeyColumns()))
+	}
+
+	var apply = func(q sqlExecutor) error {
+		for _, statement := range statements {
+			if _, err := q.ExecContext(ctx, statement); err != nil {
+				return fmt.Errorf("alter table %s: %w", r.Name, err)
+			}
+		}
+		return nil
+	}
+
+	if alter.transactionalDDL() {
+		return r.inTx(ctx, func(tx *Tx) error {
+			return apply(tx.executor())
+		})
+	}
+
+	return apply(r.directExecutor())
+}
+
+func (r *RegisteredStruct[T]) rebuildTable(ctx context.Context, existingByKey map[string]columnInfo, renameNewToOld map[string]string) error {
+	d := r.dialect()
 	tempName := fmt.Sprintf("%s__gomysql_tmp_%d", r.Name, time.Now().UnixNano())
-	createSQL := strings.Replace(r.createTableSQL, fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s", r.Name), fmt.Sprintf("CREATE TABLE %s", tempName), 1)
+	createSQL := strings.Replace(r.createTableSQL, fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s", r.quotedName()), fmt.Sprintf("CREATE TABLE %s", quoteTable(d, tempName)), 1)
 
 	var (
 		destCols          []string
@@ -339,8 +806,8 @@ func (r *RegisteredStruct[T]) rebuildTable(existingByKey map[string]columnInfo,
 		destKey := normalizeIdentifier(destName)
 		if oldKey, ok := renameNewToOld[destKey]; ok {
 			if oldCol, ok := existingByKey[oldKey]; ok {
-				destCols = append(destCols, destName)
-				srcCols = append(srcCols, oldCol.Name)
+				destCols = append(destCols, d.quoteIdent(destName))
+				srcCols = append(srcCols, d.quoteIdent(oldCol.Name))
 				mapping := copyColumnMapping{
 					destName: destName,
 					srcName:  oldCol.Name,
@@ -348,6 +815,9 @@ func (r *RegisteredStruct[T]) rebuildTable(existingByKey map[string]columnInfo,
 				if needsLegacyTimeMigration(oldCol.Type, field) {
 					mapping.transform = migrateLegacyTimeValue
 					requiresTransform = true
+				} else if needsJSONMigration(oldCol.Type, field) {
+					mapping.transform = migrateBlobToJSON(field)
+					requiresTransform = true
 				}
 				mappings = append(mappings, mapping)
 			}
@@ -355,8 +825,8 @@ func (r *RegisteredStruct[T]) rebuildTable(existingByKey map[string]columnInfo,
 		}
 
 		if col, ok := existingByKey[destKey]; ok {
-			destCols = append(destCols, destName)
-			srcCols = append(srcCols, col.Name)
+			destCols = append(destCols, d.quoteIdent(destName))
+			srcCols = append(srcCols, d.quoteIdent(col.Name))
 			mapping := copyColumnMapping{
 				destName: destName,
 				srcName:  col.Name,
@@ -364,68 +834,183 @@ func (r *RegisteredStruct[T]) rebuildTable(existingByKey map[string]columnInfo,
 			if needsLegacyTimeMigration(col.Type, field) {
 				mapping.transform = migrateLegacyTimeValue
 				requiresTransform = true
+			} else if needsJSONMigration(col.Type, field) {
+				mapping.transform = migrateBlobToJSON(field)
+				requiresTransform = true
 			}
 			mappings = append(mappings, mapping)
 		}
 	}
 
-	tx, err := r.db.db.Begin()
-	if err != nil {
-		return fmt.Errorf("begin migration %s: %w", r.Name, err)
+	copyTable := func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, createSQL); err != nil {
+			return fmt.Errorf("create temp table %s: %w", tempName, err)
+		}
+
+		if len(destCols) > 0 {
+			if requiresTransform {
+				if err := r.copyRowsWithTransform(ctx, tx, tempName, mappings); err != nil {
+					return err
+				}
+			} else {
+				insertSQL := fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s;", quoteTable(d, tempName), joinColumns(destCols), joinColumns(srcCols), r.quotedName())
+				if _, err := tx.ExecContext(ctx, insertSQL); err != nil {
+					return fmt.Errorf("copy data into %s: %w", tempName, err)
+				}
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("DROP TABLE %s;", r.quotedName())); err != nil {
+			return fmt.Errorf("drop old table %s: %w", r.Name, err)
+		}
+
+		// The new name stays in the table's database, so it takes no prefix.
+		_, base := splitTableName(r.Name)
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("ALTER TABLE %s RENAME TO %s;", quoteTable(d, tempName), d.quoteIdent(base))); err != nil {
+			return fmt.Errorf("rename temp table %s: %w", tempName, err)
+		}
+
+		return nil
+	}
+
+	if r.tx != nil {
+		return r.rebuildInTx(ctx, copyTable)
 	}
 
-	if _, err := tx.Exec("PRAGMA defer_foreign_keys = ON;"); err != nil {
-		_ = tx.Rollback()
-		return fmt.Errorf("defer foreign keys for %s: %w", r.Name, err)
+	return r.rebuildOnConn(ctx, copyTable)
+}
+
+// rebuildOnConn runs a table rebuild on a dedicated connection with foreign key enforcement
+// turned off. With enforcement on, dropping the old table would fire ON DELETE actions and
+// empty or null out the rows of child tables. The foreign keys are checked before committing.
+func (r *RegisteredStruct[T]) rebuildOnConn(ctx context.Context, copyTable func(tx *sql.Tx) error) (err error) {
+	conn, err := r.db.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("rebuild %s: %w", r.Name, err)
 	}
+	defer conn.Close()
 
-	if _, err := tx.Exec(createSQL); err != nil {
-		_ = tx.Rollback()
-		return fmt.Errorf("create temp table %s: %w", tempName, err)
+	var enforced bool
+	if err = conn.QueryRowContext(ctx, "PRAGMA foreign_keys;").Scan(&enforced); err != nil {
+		return fmt.Errorf("read foreign keys setting for %s: %w", r.Name, err)
 	}
 
-	if len(destCols) > 0 {
-		if requiresTransform {
-			if err := r.copyRowsWithTransform(tx, tempName, mappings); err != nil {
-				_ = tx.Rollback()
-				return err
-			}
-		} else {
-			insertSQL := fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s;", tempName, strings.Join(destCols, ", "), strings.Join(srcCols, ", "), r.Name)
-			if _, err := tx.Exec(insertSQL); err != nil {
-				_ = tx.Rollback()
-				return fmt.Errorf("copy data into %s: %w", tempName, err)
+	if enforced {
+		// The pragma is a no-op inside a transaction, so it is set before BEGIN.
+		if _, err = conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF;"); err != nil {
+			return fmt.Errorf("disable foreign keys for %s: %w", r.Name, err)
+		}
+
+		defer func() {
+			if _, restoreErr := conn.ExecContext(context.WithoutCancel(ctx), "PRAGMA foreign_keys = ON;"); restoreErr != nil && err == nil {
+				err = fmt.Errorf("restore foreign keys after rebuilding %s: %w", r.Name, restoreErr)
 			}
+		}()
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin rebuild of %s: %w", r.Name, err)
+	}
+
+	if err = copyTable(tx); err == nil && enforced {
+		err = checkForeignKeys(ctx, tx, r.Name)
+	}
+
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return fmt.Errorf("%w (rollback: %v)", err, rollbackErr)
+		}
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("commit rebuild of %s: %w", r.Name, err)
+	}
+
+	return nil
+}
+
+// rebuildInTx runs a table rebuild inside the caller's transaction. Foreign key enforcement
+// cannot be turned off there, so a rebuild that would fire ON DELETE actions is refused.
+func (r *RegisteredStruct[T]) rebuildInTx(ctx context.Context, copyTable func(tx *sql.Tx) error) error {
+	var enforced bool
+	if err := r.tx.tx.QueryRowContext(ctx, "PRAGMA foreign_keys;").Scan(&enforced); err != nil {
+		return fmt.Errorf("read foreign keys setting for %s: %w", r.Name, err)
+	}
+
+	if enforced {
+		referenced, err := isReferenced(ctx, r.tx.tx, r.Name)
+		if err != nil {
+			return err
+		}
+		if referenced {
+			return fmt.Errorf("rebuild %s: other tables reference it, so it cannot be rebuilt inside a transaction with foreign keys enforced", r.Name)
 		}
 	}
 
-	if _, err := tx.Exec(fmt.Sprintf("DROP TABLE %s;", r.Name)); err != nil {
-		_ = tx.Rollback()
-		return fmt.Errorf("drop old table %s: %w", r.Name, err)
+	return r.tx.savepoint(ctx, func(tx *Tx) error {
+		return copyTable(tx.tx)
+	})
+}
+
+// checkForeignKeys fails if any row in the database violates a foreign key.
+func checkForeignKeys(ctx context.Context, tx *sql.Tx, table string) error {
+	rows, err := tx.QueryContext(ctx, "PRAGMA foreign_key_check;")
+	if err != nil {
+		return fmt.Errorf("check foreign keys after rebuilding %s: %w", table, err)
 	}
+	defer rows.Close()
 
-	if _, err := tx.Exec(fmt.Sprintf("ALTER TABLE %s RENAME TO %s;", tempName, r.Name)); err != nil {
-		_ = tx.Rollback()
-		return fmt.Errorf("rename temp table %s: %w", tempName, err)
+	if rows.Next() {
+		var (
+			child, parent string
+			rowID         sql.NullInt64
+			index         int
+		)
+		if err := rows.Scan(&child, &rowID, &parent, &index); err != nil {
+			return fmt.Errorf("scan foreign key check after rebuilding %s: %w", table, err)
+		}
+		return fmt.Errorf("rebuild %s: row %d of %s violates its foreign key to %s", table, rowID.Int64, child, parent)
 	}
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("commit migration %s: %w", r.Name, err)
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("check foreign keys after rebuilding %s: %w", table, err)
 	}
 
 	return nil
 }
 
-func (r *RegisteredStruct[T]) copyRowsWithTransform(tx *sql.Tx, tempName string, mappings []copyColumnMapping) error {
+// isReferenced reports whether a foreign key of another table in table's database references it.
+func isReferenced(ctx context.Context, tx *sql.Tx, table string) (bool, error) {
+	schema, base := splitTableName(table)
+	if schema == "" {
+		schema = "main"
+	}
+	master, _ := sqliteMaster(table)
+
+	query := fmt.Sprintf(`SELECT COUNT(*) FROM %s AS m, pragma_foreign_key_list(m.name, ?) AS f
+		WHERE m.type = 'table' AND m.name <> ? AND f."table" = ? COLLATE NOCASE;`, master)
+
+	var count int
+	if err := tx.QueryRowContext(ctx, query, schema, base, base).Scan(&count); err != nil {
+		return false, fmt.Errorf("find tables referencing %s: %w", table, err)
+	}
+
+	return count > 0, nil
+}
+
+func (r *RegisteredStruct[T]) copyRowsWithTransform(ctx context.Context, tx *sql.Tx, tempName string, mappings []copyColumnMapping) error {
+	d := r.dialect()
 	destCols := make([]string, 0, len(mappings))
 	srcCols := make([]string, 0, len(mappings))
 	for _, mapping := range mappings {
-		destCols = append(destCols, mapping.destName)
-		srcCols = append(srcCols, mapping.srcName)
+		destCols = append(destCols, d.quoteIdent(mapping.destName))
+		srcCols = append(srcCols, d.quoteIdent(mapping.srcName))
 	}
 
-	querySQL := fmt.Sprintf("SELECT %s FROM %s;", strings.Join(srcCols, ", "), r.Name)
-	rows, err := tx.Query(querySQL)
+	querySQL := fmt.Sprintf("SELECT %s FROM %s;", joinColumns(srcCols), r.quotedName())
+	rows, err := tx.QueryContext(ctx, querySQL)
 	if err != nil {
 		return fmt.Errorf("query rows for migration %s: %w", r.Name, err)
 	}
@@ -433,8 +1018,8 @@ func (r *RegisteredStruct[T]) copyRowsWithTransform(tx *sql.Tx, tempName string,
 
 	insertSQL := fmt.Sprintf(
 		"INSERT INTO %s (%s) VALUES (%s);",
-		tempName,
-		strings.Join(destCols, ", "),
+		quoteTable(d, tempName),
+		joinColumns(destCols),
 		strings.Repeat("?, ", len(destCols)-1)+"?",
 	)
 
@@ -461,7 +1046,7 @@ func (r *RegisteredStruct[T]) copyRowsWithTransform(tx *sql.Tx, tempName string,
 			}
 		}
 
-		if _, err := tx.Exec(insertSQL, args...); err != nil {
+		if _, err := tx.ExecContext(ctx, insertSQL, args...); err != nil {
 			return fmt.Errorf("insert transformed row into %s: %w", tempName, err)
 		}
 	}
@@ -0,0 +1,243 @@
+package gomysql
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type DriverOptions struct {
+	// Dialect selects the database engine. It defaults to SQLite.
+	Dialect            Dialect
+	DisableForeignKeys bool
+	// WAL switches a SQLite database to write-ahead logging. It is shorthand for
+	// JournalMode "WAL".
+	WAL bool
+	// ReadPoolSize opens a separate pool of read-only SQLite connections used by Select,
+	// SelectAll, List and Count. Requires WAL and a file-backed database.
+	ReadPoolSize int
+	// StatementCacheSize is the number of dynamically built statements, such as rendered
+	// filters, kept prepared per database handle. Static statements of registered structs are
+	// always kept. Zero uses a default of 256 and a negative value disables the cache.
+	StatementCacheSize int
+	// JSON stores slice, map and struct fields as JSON text instead of gob-encoded blobs,
+	// except byte slices and fields tagged gob.
+	JSON bool
+	// CursorKey signs the cursors returned by Paginate. Drivers serving the same cursors, such
+	// as several instances behind a load balancer, need the same key. When empty, a random key
+	// is generated, so cursors only work with the driver that issued them.
+	CursorKey []byte
+
+	// The options below are SQLite pragmas applied to every new connection. Zero values
+	// leave the SQLite defaults in place.
+	BusyTimeout time.Duration
+	JournalMode string // DELETE, TRUNCATE, PERSIST, MEMORY, WAL or OFF
+	Synchronous string // OFF, NORMAL, FULL or EXTRA
+	CacheSize   int    // pages when positive, KiB when negative
+	MmapSize    int64  // bytes
+	TempStore   string // DEFAULT, FILE or MEMORY
+}
+
+var (
+	journalModes = []string{"DELETE", "TRUNCATE", "PERSIST", "MEMORY", "WAL", "OFF"}
+	syncModes    = []string{"OFF", "NORMAL", "FULL", "EXTRA"}
+	tempStores   = []string{"DEFAULT", "FILE", "MEMORY"}
+)
+
+func (o DriverOptions) journalMode() string {
+	if o.WAL {
+		return "WAL"
+	}
+
+	return strings.ToUpper(strings.TrimSpace(o.JournalMode))
+}
+
+func (o DriverOptions) statementCacheSize() int {
+	if o.StatementCacheSize == 0 {
+		return defaultStatementCacheSize
+	}
+
+	return o.StatementCacheSize
+}
+
+func (o DriverOptions) hasSQLitePragmas() bool {
+	return o.DisableForeignKeys || o.journalMode() != "" || o.ReadPoolSize != 0 || o.BusyTimeout != 0 ||
+		o.Synchronous != "" || o.CacheSize != 0 || o.MmapSize != 0 || o.TempStore != ""
+}
+
+// Validate reports options that are out of range, unknown or not supported by the dialect.
+func (o DriverOptions) Validate() error {
+	if o.dialect() != SQLite {
+		if o.hasSQLitePragmas() {
+			return fmt.Errorf("sqlite connection options: %w", ErrUnsupportedByDialect)
+		}
+		return nil
+	}
+
+	if o.WAL && o.JournalMode != "" && !strings.EqualFold(strings.TrimSpace(o.JournalMode), "WAL") {
+		return fmt.Errorf("WAL conflicts with journal mode %s", o.JournalMode)
+	}
+
+	if err := validateChoice("journal mode", o.journalMode(), journalModes); err != nil {
+		return err
+	}
+
+	if err := validateChoice("synchronous", o.Synchronous, syncModes); err != nil {
+		return err
+	}
+
+	if err := validateChoice("temp store", o.TempStore, tempStores); err != nil {
+		return err
+	}
+
+	switch {
+	case o.BusyTimeout < 0:
+		return fmt.Errorf("busy timeout must not be negative")
+	case o.BusyTimeout%time.Millisecond != 0:
+		return fmt.Errorf("busy timeout must be a whole number of milliseconds")
+	case o.MmapSize < 0:
+		return fmt.Errorf("mmap size must not be negative")
+	case o.ReadPoolSize < 0:
+		return fmt.Errorf("read pool size must not be negative")
+	case o.ReadPoolSize > 0 && o.journalMode() != "WAL":
+		return fmt.Errorf("read pool requires WAL journal mode")
+	}
+
+	return nil
+}
+
+func validateChoice(name, value string, choices []string) error {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	if value == "" {
+		return nil
+	}
+
+	for _, choice := range choices {
+		if value == choice {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid %s %q, expected one of %s", name, value, strings.Join(choices, ", "))
+}
+
+// sqlitePragmas renders the options as pragma calls in the form accepted by the
+// driver's _pragma DSN parameter, e.g. "busy_timeout(5000)".
+func sqlitePragmas(o DriverOptions, readOnly bool) []string {
+	var pragmas []string
+
+	if o.BusyTimeout > 0 {
+		pragmas = append(pragmas, fmt.Sprintf("busy_timeout(%d)", o.BusyTimeout.Milliseconds()))
+	}
+
+	if readOnly {
+		pragmas = append(pragmas, "query_only(1)")
+	} else {
+		if !o.DisableForeignKeys {
+			pragmas = append(pragmas, "foreign_keys(1)")
+		}
+
+		if mode := o.journalMode(); mode != "" {
+			pragmas = append(pragmas, fmt.Sprintf("journal_mode(%s)", mode))
+		}
+	}
+
+	if o.Synchronous != "" {
+		pragmas = append(pragmas, fmt.Sprintf("synchronous(%s)", strings.ToUpper(strings.TrimSpace(o.Synchronous))))
+	}
+
+	if o.CacheSize != 0 {
+		pragmas = append(pragmas, fmt.Sprintf("cache_size(%d)", o.CacheSize))
+	}
+
+	if o.MmapSize > 0 {
+		pragmas = append(pragmas, fmt.Sprintf("mmap_size(%d)", o.MmapSize))
+	}
+
+	if o.TempStore != "" {
+		pragmas = append(pragmas, fmt.Sprintf("temp_store(%s)", strings.ToUpper(strings.TrimSpace(o.TempStore))))
+	}
+
+	return pragmas
+}
+
+func sqliteDSN(dataSourceName string, pragmas []string) string {
+	if len(pragmas) == 0 {
+		return dataSourceName
+	}
+
+	query := make(url.Values)
+	query["_pragma"] = pragmas
+
+	separator := "?"
+	if strings.Contains(dataSourceName, "?") {
+		separator = "&"
+	}
+
+	return dataSourceName + separator + query.Encode()
+}
+
+// ParseDSN splits driver options out of a SQLite data source name such as
+// "file:app.db?busy_timeout=5s&journal_mode=WAL&read_pool_size=4". Recognized parameters
+// are busy_timeout (a duration or milliseconds), journal_mode, synchronous, cache_size,
+// mmap_size, temp_store, foreign_keys and read_pool_size. Other parameters are kept in the
+// returned data source name.
+func ParseDSN(dsn string) (dataSourceName string, opts DriverOptions, err error) {
+	base, rawQuery, found := strings.Cut(dsn, "?")
+	if !found {
+		return dsn, opts, nil
+	}
+
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return "", opts, fmt.Errorf("parse dsn %q: %w", dsn, err)
+	}
+
+	for key, values := range query {
+		value := values[len(values)-1]
+		switch key {
+		case "busy_timeout":
+			if ms, convErr := strconv.Atoi(value); convErr == nil {
+				opts.BusyTimeout = time.Duration(ms) * time.Millisecond
+			} else {
+				opts.BusyTimeout, err = time.ParseDuration(value)
+			}
+		case "journal_mode":
+			opts.JournalMode = value
+		case "synchronous":
+			opts.Synchronous = value
+		case "cache_size":
+			opts.CacheSize, err = strconv.Atoi(value)
+		case "mmap_size":
+			opts.MmapSize, err = strconv.ParseInt(value, 10, 64)
+		case "temp_store":
+			opts.TempStore = value
+		case "foreign_keys":
+			var enabled bool
+			enabled, err = strconv.ParseBool(value)
+			opts.DisableForeignKeys = !enabled
+		case "read_pool_size":
+			opts.ReadPoolSize, err = strconv.Atoi(value)
+		default:
+			continue
+		}
+
+		if err != nil {
+			return "", opts, fmt.Errorf("parse dsn option %s: %w", key, err)
+		}
+		delete(query, key)
+	}
+
+	if err = opts.Validate(); err != nil {
+		return "", opts, err
+	}
+
+	dataSourceName = base
+	if len(query) > 0 {
+		dataSourceName += "?" + query.Encode()
+	}
+
+	return dataSourceName, opts, nil
+}
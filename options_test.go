@@ -0,0 +1,156 @@
+package gomysql
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func pragmaValue(t *testing.T, db *sql.DB, conns int, pragma string) []string {
+	t.Helper()
+
+	var (
+		ctx    = context.Background()
+		held   []*sql.Conn
+		values []string
+	)
+
+	defer func() {
+		for _, conn := range held {
+			conn.Close()
+		}
+	}()
+
+	// Hold every connection open so each query lands on a distinct one.
+	for range conns {
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			t.Fatalf("failed to get connection: %v", err)
+		}
+		held = append(held, conn)
+
+		var value string
+		if err := conn.QueryRowContext(ctx, "PRAGMA "+pragma+";").Scan(&value); err != nil {
+			t.Fatalf("failed to read pragma %s: %v", pragma, err)
+		}
+		values = append(values, strings.ToLower(value))
+	}
+
+	return values
+}
+
+func TestOpenAppliesPragmasToEveryConnection(t *testing.T) {
+	driver, err := Open(filepath.Join(t.TempDir(), "pragmas.db"), DriverOptions{
+		WAL:          true,
+		ReadPoolSize: 3,
+		BusyTimeout:  2500 * time.Millisecond,
+		Synchronous:  "normal",
+		CacheSize:    -4000,
+		MmapSize:     1 << 20,
+		TempStore:    "MEMORY",
+	})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer driver.Close()
+
+	assert.Equal(t, []string{"wal"}, pragmaValue(t, driver.db, 1, "journal_mode"))
+	assert.Equal(t, []string{"1"}, pragmaValue(t, driver.db, 1, "foreign_keys"))
+	assert.Equal(t, []string{"2500"}, pragmaValue(t, driver.db, 1, "busy_timeout"))
+
+	assert.Equal(t, []string{"2500", "2500", "2500"}, pragmaValue(t, driver.readDB, 3, "busy_timeout"))
+	assert.Equal(t, []string{"1", "1", "1"}, pragmaValue(t, driver.readDB, 3, "synchronous"))
+	assert.Equal(t, []string{"-4000", "-4000", "-4000"}, pragmaValue(t, driver.readDB, 3, "cache_size"))
+	assert.Equal(t, []string{"1048576", "1048576", "1048576"}, pragmaValue(t, driver.readDB, 3, "mmap_size"))
+	assert.Equal(t, []string{"2", "2", "2"}, pragmaValue(t, driver.readDB, 3, "temp_store"))
+	assert.Equal(t, []string{"1", "1", "1"}, pragmaValue(t, driver.readDB, 3, "query_only"))
+}
+
+func TestOpenDBAppliesPragmas(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+
+	driver, err := OpenDB(db, DriverOptions{BusyTimeout: time.Second, DisableForeignKeys: true})
+	if err != nil {
+		t.Fatalf("failed to wrap database: %v", err)
+	}
+	defer driver.Close()
+
+	assert.Equal(t, []string{"1000"}, pragmaValue(t, driver.db, 1, "busy_timeout"))
+	assert.Equal(t, []string{"0"}, pragmaValue(t, driver.db, 1, "foreign_keys"))
+}
+
+func TestDriverOptionsValidate(t *testing.T) {
+	invalid := map[string]DriverOptions{
+		"journal mode":      {JournalMode: "fast"},
+		"synchronous":       {Synchronous: "sometimes"},
+		"temp store":        {TempStore: "disk"},
+		"negative timeout":  {BusyTimeout: -time.Second},
+		"partial ms":        {BusyTimeout: 1500 * time.Microsecond},
+		"negative mmap":     {MmapSize: -1},
+		"wal conflict":      {WAL: true, JournalMode: "DELETE"},
+		"pool without wal":  {ReadPoolSize: 2, JournalMode: "TRUNCATE"},
+		"negative pool":     {ReadPoolSize: -1},
+		"pragma on mysql":   {Dialect: MySQL, BusyTimeout: time.Second},
+		"pragma on postgre": {Dialect: Postgres, Synchronous: "OFF"},
+	}
+
+	for name, opts := range invalid {
+		assert.Error(t, opts.Validate(), name)
+	}
+
+	valid := []DriverOptions{
+		{},
+		{JournalMode: "wal", ReadPoolSize: 2},
+		{WAL: true, JournalMode: "WAL"},
+		{Synchronous: "extra", TempStore: "file", CacheSize: 2000},
+		{Dialect: MySQL},
+	}
+
+	for _, opts := range valid {
+		assert.NoError(t, opts.Validate())
+	}
+}
+
+func TestParseDSN(t *testing.T) {
+	dsn, opts, err := ParseDSN("file:app.db?busy_timeout=5s&journal_mode=WAL&synchronous=NORMAL&cache_size=-2000&mmap_size=4096&temp_store=MEMORY&foreign_keys=false&read_pool_size=4&vfs=unix")
+	if err != nil {
+		t.Fatalf("failed to parse dsn: %v", err)
+	}
+
+	assert.Equal(t, "file:app.db?vfs=unix", dsn)
+	assert.Equal(t, DriverOptions{
+		DisableForeignKeys: true,
+		ReadPoolSize:       4,
+		BusyTimeout:        5 * time.Second,
+		JournalMode:        "WAL",
+		Synchronous:        "NORMAL",
+		CacheSize:          -2000,
+		MmapSize:           4096,
+		TempStore:          "MEMORY",
+	}, opts)
+
+	_, opts, err = ParseDSN("app.db?busy_timeout=750")
+	if err != nil {
+		t.Fatalf("failed to parse dsn: %v", err)
+	}
+	assert.Equal(t, 750*time.Millisecond, opts.BusyTimeout)
+
+	dsn, opts, err = ParseDSN("app.db")
+	assert.NoError(t, err)
+	assert.Equal(t, "app.db", dsn)
+	assert.Equal(t, DriverOptions{}, opts)
+
+	_, _, err = ParseDSN("app.db?cache_size=lots")
+	assert.Error(t, err)
+
+	_, _, err = ParseDSN("app.db?journal_mode=sometimes")
+	assert.Error(t, err)
+}
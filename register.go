@@ -3,6 +3,7 @@ package gomysql
 import (
 	"fmt"
 	"reflect"
+	"slices"
 )
 
 func resolveInternalType(t reflect.Type) (TypeRepresentation, error) {
@@ -39,7 +40,22 @@ func resolveInternalType(t reflect.Type) (TypeRepresentation, error) {
 	}
 }
 
+// TableNamer is implemented by structs stored in a table not named after the struct. The name
+// may carry a schema or attached-database prefix, as in "archive.users".
+type TableNamer interface {
+	TableName() string
+}
+
 func Register[T any](structInstance T) (registered *RegisteredStruct[T], err error) {
+	return RegisterOn(DB, structInstance)
+}
+
+func RegisterOn[T any](driver *Driver, structInstance T) (registered *RegisteredStruct[T], err error) {
+	if driver == nil {
+		err = ErrDatabaseNotInitialized
+		return
+	}
+
 	var structType reflect.Type = reflect.TypeOf(structInstance)
 
 	if structType.Kind() == reflect.Pointer {
@@ -51,56 +67,156 @@ func Register[T any](structInstance T) (registered *RegisteredStruct[T], err err
 		return
 	}
 
+	tableName := structType.Name()
+	if namer, ok := reflect.New(structType).Interface().(TableNamer); ok {
+		tableName = namer.TableName()
+		if !validTableName(tableName) {
+			err = fmt.Errorf("invalid table name %q for struct %s", tableName, structType.Name())
+			return
+		}
+	}
+
 	registered = &RegisteredStruct[T]{
-		db:     DB,
-		Name:   structType.Name(),
+		db:     driver,
+		Name:   tableName,
 		Type:   structType,
 		Fields: make([]RegisteredStructField, 0),
 	}
 
-	for i := range structType.NumField() {
-		var field reflect.StructField = structType.Field(i)
-		if tag, ok := field.Tag.Lookup("gomysql"); ok {
-			var opts = mustParseTag(tag)
-
-			internalType, err := resolveInternalType(field.Type)
-			if err != nil {
-				return nil, fmt.Errorf("%w for field %s", err, field.Name)
-			}
-
-			registered.Fields = append(registered.Fields, RegisteredStructField{
-				Opts:         opts,
-				RealName:     field.Name,
-				Type:         field.Type,
-				Index:        field.Index,
-				InternalType: internalType,
-			})
-		}
+	if err = registered.registerFields(structType, nil, "", ""); err != nil {
+		return nil, err
 	}
 
-	// Multiple primary keys or no primary key is not allowed
-	var primaryKeyCount int
+	// Several primary key fields form a composite key, which cannot auto-increment
+	var primaryKeyCount, autoIncrementKeys int
 	for _, field := range registered.Fields {
 		if field.Opts.PrimaryKey {
 			primaryKeyCount++
+			if field.Opts.AutoIncr {
+				autoIncrementKeys++
+			}
 		}
 	}
 
-	if primaryKeyCount > 1 {
-		err = fmt.Errorf("multiple primary keys are not allowed in struct %s", structType.Name())
-	} else if primaryKeyCount == 0 {
+	if primaryKeyCount == 0 {
 		err = fmt.Errorf("no primary key defined in struct %s", structType.Name())
+	} else if primaryKeyCount > 1 && autoIncrementKeys > 0 {
+		err = fmt.Errorf("auto-increment is not allowed in the composite primary key of struct %s", structType.Name())
+	}
+
+	for i, field := range registered.Fields {
+		if ref := field.Opts.ForeignKey; ref != nil && err == nil {
+			// A struct referencing itself is not registered yet, so its own table is used.
+			resolved := *ref
+			if ref.TableName == structType.Name() {
+				resolved.TableName = tableName
+			} else {
+				resolved.TableName, err = driver.resolveTable(ref.TableName)
+			}
+
+			if err == nil {
+				registered.Fields[i].Opts.ForeignKey = &resolved
+			}
+		}
+
+		if ref := field.Opts.ForeignKey; ref != nil && (ref.OnDelete == "SET NULL" || ref.OnUpdate == "SET NULL") &&
+			(field.Opts.NotNull || field.Opts.PrimaryKey) {
+			err = fmt.Errorf("SET NULL foreign key action on non-nullable field %s of struct %s", field.RealName, structType.Name())
+		}
 	}
 
 	if err != nil {
 		return nil, err
 	}
 
+	if err = registered.collectIndexes(); err != nil {
+		return nil, err
+	}
+
 	generateSQLStatements(registered)
+	registered.pinStatements()
 
 	if err = registered.runCreation(); err != nil {
 		return nil, err
 	}
 
+	// Only a successful registration claims the table as an fkey: target, so a corrected
+	// struct can be registered again after a failure.
+	driver.registerTable(structType.Name(), tableName)
 	return
 }
+
+// registerFields adds the tagged fields of structType as columns, recursing into untagged
+// anonymous embedded structs and fields tagged inline. Their fields are named by dotted Go
+// paths such as Audit.CreatedAt, and their columns take the accumulated prefix.
+func (r *RegisteredStruct[T]) registerFields(structType reflect.Type, index []int, path, prefix string) error {
+	for i := range structType.NumField() {
+		var (
+			field  reflect.StructField = structType.Field(i)
+			goName                     = path + field.Name
+			opts   SQLTagOpts
+		)
+
+		tag, tagged := field.Tag.Lookup("gomysql")
+		if tagged {
+			opts = mustParseTag(tag)
+		} else if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			opts.Inline = true
+		} else {
+			continue
+		}
+
+		fieldIndex := append(slices.Clone(index), i)
+		if opts.Inline {
+			if field.Type.Kind() != reflect.Struct {
+				return fmt.Errorf("inline field %s of struct %s must be a struct, got %s", goName, r.Type.Name(), field.Type)
+			}
+
+			if err := r.registerFields(field.Type, fieldIndex, goName+".", prefix+opts.Prefix); err != nil {
+				return err
+			}
+			continue
+		}
+
+		opts.KeyName = prefix + opts.KeyName
+		if r.FieldBySQLName(opts.KeyName) != nil {
+			return fmt.Errorf("duplicate column %s for field %s of struct %s", opts.KeyName, goName, r.Type.Name())
+		}
+
+		internalType, codec, err := resolveFieldType(field.Type)
+		if err != nil {
+			return fmt.Errorf("%w for field %s", err, goName)
+		}
+
+		if internalType, err = r.storageType(field.Type, internalType, opts); err != nil {
+			return fmt.Errorf("%w for field %s", err, goName)
+		}
+
+		r.Fields = append(r.Fields, RegisteredStructField{
+			Opts:         opts,
+			RealName:     goName,
+			Type:         field.Type,
+			Index:        fieldIndex,
+			InternalType: internalType,
+			codec:        codec,
+		})
+	}
+
+	return nil
+}
+
+// storageType picks JSON over gob for slice, map and struct fields tagged json, or for all of
+// them but byte slices when the driver stores JSON by default.
+func (r *RegisteredStruct[T]) storageType(t reflect.Type, internalType TypeRepresentation, opts SQLTagOpts) (TypeRepresentation, error) {
+	blob := internalType == TypeRepArrayBlob || internalType == TypeRepMapBlob || internalType == TypeRepStructBlob
+	switch {
+	case (opts.JSON || opts.Gob) && !blob:
+		return 0, fmt.Errorf("json and gob options require a slice, array, map or struct, got %s", t)
+	case opts.JSON:
+		return TypeRepJSON, nil
+	case blob && !opts.Gob && r.db.opts.JSON && baseTypeOf(t) != bytesType:
+		return TypeRepJSON, nil
+	default:
+		return internalType, nil
+	}
+}
@@ -0,0 +1,383 @@
+package gomysql
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Aggregate is an aggregate function over a column, built with SumOf, AvgOf, MinOf, MaxOf or
+// CountRows, for GroupBy queries and Filter.AggregateCmp.
+type Aggregate struct {
+	function string
+	field    *RegisteredStructField // nil for COUNT(*)
+}
+
+// SumOf sums a numeric field. Its GroupRow value is an int64 for signed integer fields, a
+// uint64 for unsigned ones and a float64 for floats. SQLite sums uint64 values above
+// math.MaxInt64, which it stores as blobs, as 0, and so does AvgOf.
+func SumOf(field *RegisteredStructField) Aggregate {
+	return Aggregate{function: "SUM", field: field}
+}
+
+// AvgOf averages a numeric field. Its GroupRow value is a float64.
+func AvgOf(field *RegisteredStructField) Aggregate {
+	return Aggregate{function: "AVG", field: field}
+}
+
+// MinOf returns the smallest value of a field. Its GroupRow value has the field's type.
+func MinOf(field *RegisteredStructField) Aggregate {
+	return Aggregate{function: "MIN", field: field}
+}
+
+// MaxOf returns the largest value of a field. Its GroupRow value has the field's type.
+func MaxOf(field *RegisteredStructField) Aggregate {
+	return Aggregate{function: "MAX", field: field}
+}
+
+// CountRows counts the rows of a group. Its GroupRow value is an int64.
+func CountRows() Aggregate {
+	return Aggregate{function: "COUNT"}
+}
+
+func (a Aggregate) expr() string {
+	if a.field == nil {
+		return a.function + "(*)"
+	}
+	return a.function + "(" + markIdent(a.field.Opts.KeyName) + ")"
+}
+
+// resultType is the Go type an aggregate's value is decoded into.
+func (a Aggregate) resultType() reflect.Type {
+	switch {
+	case a.field == nil:
+		return reflect.TypeFor[int64]()
+	case a.function == "AVG":
+		return reflect.TypeFor[float64]()
+	case a.function != "SUM":
+		return a.field.Type
+	}
+
+	switch a.field.InternalType {
+	case TypeRepUint:
+		return reflect.TypeFor[uint64]()
+	case TypeRepFloat:
+		return reflect.TypeFor[float64]()
+	default:
+		return reflect.TypeFor[int64]()
+	}
+}
+
+// check reports an aggregate that is not built by a constructor, belongs to another struct or
+// sums or averages a field that is not numeric.
+func (a Aggregate) check(hasField func(*RegisteredStructField) bool) error {
+	switch {
+	case a.function == "":
+		return fmt.Errorf("aggregate must be built with SumOf, AvgOf, MinOf, MaxOf or CountRows")
+	case a.field == nil && a.function != "COUNT":
+		return fmt.Errorf("%s requires a valid field", a.function)
+	case a.field == nil:
+		return nil
+	case !hasField(a.field):
+		return fmt.Errorf("%s requires a field of the queried struct, got %s", a.function, a.field.Opts.KeyName)
+	}
+
+	if a.function == "SUM" || a.function == "AVG" {
+		switch a.field.InternalType {
+		case TypeRepInt, TypeRepUint, TypeRepFloat:
+		default:
+			return fmt.Errorf("%s requires a numeric field, got %s", a.function, a.field.Opts.KeyName)
+		}
+	}
+
+	return nil
+}
+
+// decodeAggregate decodes an aggregate result into target, whose type may differ from the
+// field's, as when summing int fields into an int64.
+func decodeAggregate(target reflect.Value, field *RegisteredStructField, raw any) error {
+	decodeField := RegisteredStructField{Type: target.Type()}
+	if field != nil {
+		decodeField.Opts = field.Opts
+		decodeField.RealName = field.RealName
+		decodeField.Index = field.Index
+		decodeField.InternalType = field.InternalType
+		decodeField.codec = field.codec
+	}
+
+	if field == nil || target.Type() != field.Type {
+		internalType, codec, err := resolveFieldType(target.Type())
+		if err != nil {
+			return err
+		}
+		decodeField.InternalType, decodeField.codec = internalType, codec
+	}
+
+	return assignDecodedValue(target, decodeField, raw)
+}
+
+// Sum returns the sum of a numeric field over the rows matching filter, or every row for a nil
+// filter, as V, such as int64 or float64. No rows sum to V's zero value, or nil for a pointer V.
+func Sum[V, T any](r *RegisteredStruct[T], field *RegisteredStructField, filter *Filter) (V, error) {
+	return SumCtx[V](context.Background(), r, field, filter)
+}
+
+func SumCtx[V, T any](ctx context.Context, r *RegisteredStruct[T], field *RegisteredStructField, filter *Filter) (V, error) {
+	return aggregate[V](ctx, r, SumOf(field), filter)
+}
+
+// Avg returns the average of a numeric field over the rows matching filter, or every row for a
+// nil filter, and 0 without rows.
+func Avg[T any](r *RegisteredStruct[T], field *RegisteredStructField, filter *Filter) (float64, error) {
+	return AvgCtx(context.Background(), r, field, filter)
+}
+
+func AvgCtx[T any](ctx context.Context, r *RegisteredStruct[T], field *RegisteredStructField, filter *Filter) (float64, error) {
+	return aggregate[float64](ctx, r, AvgOf(field), filter)
+}
+
+// Min returns the smallest value of a field over the rows matching filter, or every row for a
+// nil filter, as V, usually the field's type. Use a pointer V to tell no rows, which give nil,
+// from a zero minimum.
+func Min[V, T any](r *RegisteredStruct[T], field *RegisteredStructField, filter *Filter) (V, error) {
+	return MinCtx[V](context.Background(), r, field, filter)
+}
+
+func MinCtx[V, T any](ctx context.Context, r *RegisteredStruct[T], field *RegisteredStructField, filter *Filter) (V, error) {
+	return aggregate[V](ctx, r, MinOf(field), filter)
+}
+
+// Max returns the largest value of a field like Min.
+func Max[V, T any](r *RegisteredStruct[T], field *RegisteredStructField, filter *Filter) (V, error) {
+	return MaxCtx[V](context.Background(), r, field, filter)
+}
+
+func MaxCtx[V, T any](ctx context.Context, r *RegisteredStruct[T], field *RegisteredStructField, filter *Filter) (V, error) {
+	return aggregate[V](ctx, r, MaxOf(field), filter)
+}
+
+func aggregate[V, T any](ctx context.Context, r *RegisteredStruct[T], agg Aggregate, filter *Filter) (result V, err error) {
+	if r.db == nil {
+		return result, ErrDatabaseNotInitialized
+	}
+
+	if err = agg.check(r.hasField); err != nil {
+		return result, err
+	}
+
+	sql, args, err := r.buildAggregateSQL(agg, filter)
+	if err != nil {
+		return result, err
+	}
+
+	q, release, err := r.readExecutor(ctx)
+	if err != nil {
+		return result, err
+	}
+	defer release()
+
+	var raw any
+	if err = q.QueryRowContext(ctx, sql, args...).Scan(&raw); err != nil {
+		return result, fmt.Errorf("%s fail %s: %w", strings.ToLower(agg.function), r.Name, err)
+	}
+
+	if err = decodeAggregate(reflect.ValueOf(&result).Elem(), agg.field, raw); err != nil {
+		return result, fmt.Errorf("convert %s of %s: %w", strings.ToLower(agg.function), r.Name, err)
+	}
+
+	return result, nil
+}
+
+// buildAggregateSQL mirrors buildCountSQL: a filter with ordering, limit or offset selects the
+// rows in a subquery that the aggregate runs over.
+func (r *RegisteredStruct[T]) buildAggregateSQL(agg Aggregate, filter *Filter) (string, []any, error) {
+	expr := renderIdents(r.dialect(), agg.expr())
+
+	switch {
+	case filterHasSelectionModifiers(filter):
+		filterClause, filterArgs, err := buildFilterClause(r.dialect(), filter)
+		if err != nil {
+			return "", nil, err
+		}
+
+		sql := fmt.Sprintf("SELECT %s FROM (SELECT %s FROM %s", expr, r.quotedColumn(*agg.field), r.quotedName())
+		if filterClause != "" {
+			sql += " " + filterClause
+		}
+		sql += ") AS filtered_rows;"
+		return sql, filterArgs, nil
+	case filterHasWhere(filter):
+		whereClause, whereArgs, err := buildWhereClause(r.dialect(), filter)
+		if err != nil {
+			return "", nil, err
+		}
+
+		return fmt.Sprintf("SELECT %s FROM %s %s;", expr, r.quotedName(), whereClause), whereArgs, nil
+	default:
+		return fmt.Sprintf("SELECT %s FROM %s;", expr, r.quotedName()), nil, nil
+	}
+}
+
+// GroupQuery aggregates the rows of a registered struct per distinct value of its group
+// fields. It is built with GroupBy and run with Aggregate.
+type GroupQuery[T any] struct {
+	r             *RegisteredStruct[T]
+	fields        []*RegisteredStructField
+	where, having *Filter
+}
+
+// GroupRow is one group returned by GroupQuery.Aggregate.
+type GroupRow[T any] struct {
+	Group  *T    // the group fields set, other fields zero
+	Values []any // one per aggregate, of the types documented on SumOf, AvgOf, MinOf, MaxOf and CountRows
+}
+
+// GroupBy starts an aggregate query grouped by fields. Without fields the aggregates run over
+// all matching rows as one group.
+func (r *RegisteredStruct[T]) GroupBy(fields ...*RegisteredStructField) *GroupQuery[T] {
+	return &GroupQuery[T]{r: r, fields: fields}
+}
+
+// Where restricts the rows that are grouped. The filter must not set ordering, limit or offset.
+func (g *GroupQuery[T]) Where(filter *Filter) *GroupQuery[T] {
+	g.where = filter
+	return g
+}
+
+// Having restricts the groups that are returned. Its conditions compare group fields with
+// KeyCmp and aggregates with AggregateCmp; its ordering, limit and offset apply to the groups.
+func (g *GroupQuery[T]) Having(filter *Filter) *GroupQuery[T] {
+	g.having = filter
+	return g
+}
+
+// Aggregate runs the query and returns one row per group, with one value per aggregate.
+func (g *GroupQuery[T]) Aggregate(aggregates ...Aggregate) ([]GroupRow[T], error) {
+	return g.AggregateCtx(context.Background(), aggregates...)
+}
+
+func (g *GroupQuery[T]) AggregateCtx(ctx context.Context, aggregates ...Aggregate) ([]GroupRow[T], error) {
+	r := g.r
+	if r.db == nil {
+		return nil, ErrDatabaseNotInitialized
+	}
+
+	sql, args, err := g.build(aggregates)
+	if err != nil {
+		return nil, err
+	}
+
+	q, release, err := r.readExecutor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	rows, err := q.QueryContext(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("aggregate fail %s: %w", r.Name, err)
+	}
+	defer rows.Close()
+
+	values := make([]any, len(g.fields)+len(aggregates))
+	scanArgs := make([]any, len(values))
+	for i := range values {
+		scanArgs[i] = &values[i]
+	}
+
+	var results []GroupRow[T]
+	for rows.Next() {
+		if err := rows.Scan(scanArgs...); err != nil {
+			return nil, fmt.Errorf("failed to scan aggregate from %s: %w", r.Name, err)
+		}
+
+		row := GroupRow[T]{Group: new(T), Values: make([]any, len(aggregates))}
+		elem := reflect.ValueOf(row.Group).Elem()
+		for i, field := range g.fields {
+			if err := assignDecodedValue(elem.FieldByIndex(field.Index), *field, values[i]); err != nil {
+				return nil, err
+			}
+		}
+
+		for i, agg := range aggregates {
+			value := reflect.New(agg.resultType()).Elem()
+			if err := decodeAggregate(value, agg.field, values[len(g.fields)+i]); err != nil {
+				return nil, fmt.Errorf("convert %s of %s: %w", strings.ToLower(agg.function), r.Name, err)
+			}
+			row.Values[i] = value.Interface()
+		}
+
+		results = append(results, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error for %s: %w", r.Name, err)
+	}
+
+	return results, nil
+}
+
+// build renders SELECT groups, aggregates FROM table WHERE ... GROUP BY groups HAVING ...
+// followed by the having filter's ordering, limit and offset.
+func (g *GroupQuery[T]) build(aggregates []Aggregate) (string, []any, error) {
+	var (
+		r       = g.r
+		d       = r.dialect()
+		columns []string
+		groups  []string
+		args    []any
+	)
+
+	if len(aggregates) == 0 {
+		return "", nil, fmt.Errorf("aggregate of %s requires at least one aggregate", r.Name)
+	}
+
+	for _, field := range g.fields {
+		if field == nil || !r.hasField(field) {
+			return "", nil, fmt.Errorf("GroupBy requires fields of %s", r.Name)
+		}
+		groups = append(groups, r.quotedColumn(*field))
+	}
+	columns = append(columns, groups...)
+
+	for _, agg := range aggregates {
+		if err := agg.check(r.hasField); err != nil {
+			return "", nil, err
+		}
+		columns = append(columns, renderIdents(d, agg.expr()))
+	}
+
+	sql := fmt.Sprintf("SELECT %s FROM %s", strings.Join(columns, ", "), r.quotedName())
+
+	if filterHasSelectionModifiers(g.where) {
+		return "", nil, fmt.Errorf("GroupBy where filter must not set ordering, limit or offset; set them on the having filter")
+	}
+
+	whereClause, whereArgs, err := buildWhereClause(d, g.where)
+	if err != nil {
+		return "", nil, err
+	}
+	if whereClause != "" {
+		sql += " " + whereClause
+		args = append(args, whereArgs...)
+	}
+
+	if len(groups) > 0 {
+		sql += " GROUP BY " + strings.Join(groups, ", ")
+	}
+
+	havingClause, havingArgs, err := buildFilterClause(d, g.having)
+	if err != nil {
+		return "", nil, err
+	}
+	if havingClause != "" {
+		if conditions, ok := strings.CutPrefix(havingClause, "WHERE "); ok {
+			havingClause = "HAVING " + conditions
+		}
+		sql += " " + havingClause
+		args = append(args, havingArgs...)
+	}
+
+	return sql + ";", args, nil
+}
@@ -1,12 +1,23 @@
 package gomysql
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 func (r *RegisteredStruct[T]) Count() (int64, error) {
-	return r.CountWithFilter(nil)
+	return r.CountWithFilterCtx(context.Background(), nil)
+}
+
+func (r *RegisteredStruct[T]) CountCtx(ctx context.Context) (int64, error) {
+	return r.CountWithFilterCtx(ctx, nil)
 }
 
 func (r *RegisteredStruct[T]) CountWithFilter(filter *Filter) (int64, error) {
+	return r.CountWithFilterCtx(context.Background(), filter)
+}
+
+func (r *RegisteredStruct[T]) CountWithFilterCtx(ctx context.Context, filter *Filter) (int64, error) {
 	if r.db == nil {
 		return 0, ErrDatabaseNotInitialized
 	}
@@ -16,11 +27,14 @@ func (r *RegisteredStruct[T]) CountWithFilter(filter *Filter) (int64, error) {
 		return 0, err
 	}
 
-	r.db.lock.Lock()
-	defer r.db.lock.Unlock()
+	q, release, err := r.readExecutor(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer release()
 
 	var count int64
-	if err := r.db.db.QueryRow(sql, args...).Scan(&count); err != nil {
+	if err := q.QueryRowContext(ctx, sql, args...).Scan(&count); err != nil {
 		return 0, fmt.Errorf("count fail %s: %w", r.Name, err)
 	}
 
@@ -30,25 +44,25 @@ func (r *RegisteredStruct[T]) CountWithFilter(filter *Filter) (int64, error) {
 func (r *RegisteredStruct[T]) buildCountSQL(filter *Filter) (string, []any, error) {
 	switch {
 	case filterHasSelectionModifiers(filter):
-		filterClause, filterArgs, err := buildFilterClause(filter)
+		filterClause, filterArgs, err := buildFilterClause(r.dialect(), filter)
 		if err != nil {
 			return "", nil, err
 		}
 
-		sql := fmt.Sprintf("SELECT COUNT(*) FROM (SELECT %s FROM %s", r.PrimaryKeyField.Opts.KeyName, r.Name)
+		sql := fmt.Sprintf("SELECT COUNT(*) FROM (SELECT %s FROM %s", r.quotedKeyColumns(), r.quotedName())
 		if filterClause != "" {
 			sql += " " + filterClause
 		}
 		sql += ") AS filtered_rows;"
 		return sql, filterArgs, nil
 	case filterHasWhere(filter):
-		whereClause, whereArgs, err := buildWhereClause(filter)
+		whereClause, whereArgs, err := buildWhereClause(r.dialect(), filter)
 		if err != nil {
 			return "", nil, err
 		}
 
-		return fmt.Sprintf("SELECT COUNT(*) FROM %s %s;", r.Name, whereClause), whereArgs, nil
+		return fmt.Sprintf("SELECT COUNT(*) FROM %s %s;", r.quotedName(), whereClause), whereArgs, nil
 	default:
-		return fmt.Sprintf("SELECT COUNT(*) FROM %s;", r.Name), nil, nil
+		return fmt.Sprintf("SELECT COUNT(*) FROM %s;", r.quotedName()), nil, nil
 	}
 }
@@ -1,17 +1,27 @@
 package gomysql
 
+import "context"
+
 func (r *RegisteredStruct[T]) runCreation() (err error) {
 	if r.db == nil {
 		err = ErrDatabaseNotInitialized
 		return
 	}
 
-	r.db.lock.Lock()
-	defer r.db.lock.Unlock()
+	ctx := context.Background()
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return
+	}
+	defer release()
 
-	if _, err = r.db.db.Exec(r.createTableSQL); err != nil {
+	if _, err = r.directExecutor().ExecContext(ctx, r.createTableSQL); err != nil {
 		return
 	}
 
+	if len(r.indexes) > 0 {
+		err = r.createMissingIndexes(ctx, r.directExecutor())
+	}
+
 	return
 }
@@ -1,16 +1,31 @@
 package gomysql
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 func (r *RegisteredStruct[T]) Delete(primaryKeyValue any) error {
+	return r.DeleteCtx(context.Background(), primaryKeyValue)
+}
+
+func (r *RegisteredStruct[T]) DeleteCtx(ctx context.Context, primaryKeyValue any) error {
 	if r.db == nil {
 		return ErrDatabaseNotInitialized
 	}
 
-	r.db.lock.Lock()
-	defer r.db.lock.Unlock()
+	keyArgs, err := r.keyArgs(primaryKeyValue)
+	if err != nil {
+		return err
+	}
+
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
 
-	if _, err := r.db.db.Exec(r.deleteSQL, primaryKeyValue); err != nil {
+	if _, err := r.executor().ExecContext(ctx, r.deleteSQL, keyArgs...); err != nil {
 		return fmt.Errorf("delete fail %s: %w", r.Name, err)
 	}
 
@@ -18,6 +33,10 @@ func (r *RegisteredStruct[T]) Delete(primaryKeyValue any) error {
 }
 
 func (r *RegisteredStruct[T]) DeleteWithFilter(filter *Filter) (int64, error) {
+	return r.DeleteWithFilterCtx(context.Background(), filter)
+}
+
+func (r *RegisteredStruct[T]) DeleteWithFilterCtx(ctx context.Context, filter *Filter) (int64, error) {
 	if r.db == nil {
 		return 0, ErrDatabaseNotInitialized
 	}
@@ -27,10 +46,13 @@ func (r *RegisteredStruct[T]) DeleteWithFilter(filter *Filter) (int64, error) {
 		return 0, err
 	}
 
-	r.db.lock.Lock()
-	defer r.db.lock.Unlock()
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer release()
 
-	result, err := r.db.db.Exec(sql, args...)
+	result, err := r.executor().ExecContext(ctx, sql, args...)
 	if err != nil {
 		return 0, fmt.Errorf("delete with filter fail %s: %w", r.Name, err)
 	}
@@ -46,26 +68,32 @@ func (r *RegisteredStruct[T]) DeleteWithFilter(filter *Filter) (int64, error) {
 func (r *RegisteredStruct[T]) buildDeleteWithFilterSQL(filter *Filter) (string, []any, error) {
 	switch {
 	case filterHasSelectionModifiers(filter):
-		filterClause, filterArgs, err := buildFilterClause(filter)
+		filterClause, filterArgs, err := buildFilterClause(r.dialect(), filter)
 		if err != nil {
 			return "", nil, err
 		}
 
-		pk := r.PrimaryKeyField.Opts.KeyName
-		sql := fmt.Sprintf("DELETE FROM %s WHERE %s IN (SELECT %s FROM %s", r.Name, pk, pk, r.Name)
+		// The derived table lets MySQL apply LIMIT to a subquery on the table being deleted from.
+		// Composite keys are matched as row values.
+		pk := r.quotedKeyColumns()
+		match := pk
+		if len(r.PrimaryKeyFields) > 1 {
+			match = "(" + pk + ")"
+		}
+		sql := fmt.Sprintf("DELETE FROM %s WHERE %s IN (SELECT %s FROM (SELECT %s FROM %s", r.quotedName(), match, pk, pk, r.quotedName())
 		if filterClause != "" {
 			sql += " " + filterClause
 		}
-		sql += ");"
+		sql += ") AS filtered_rows);"
 		return sql, filterArgs, nil
 	case filterHasWhere(filter):
-		whereClause, whereArgs, err := buildWhereClause(filter)
+		whereClause, whereArgs, err := buildWhereClause(r.dialect(), filter)
 		if err != nil {
 			return "", nil, err
 		}
 
-		return fmt.Sprintf("DELETE FROM %s %s;", r.Name, whereClause), whereArgs, nil
+		return fmt.Sprintf("DELETE FROM %s %s;", r.quotedName(), whereClause), whereArgs, nil
 	default:
-		return fmt.Sprintf("DELETE FROM %s;", r.Name), nil, nil
+		return fmt.Sprintf("DELETE FROM %s;", r.quotedName()), nil, nil
 	}
 }
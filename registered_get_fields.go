@@ -1,5 +1,7 @@
 package gomysql
 
+import "slices"
+
 func (r *RegisteredStruct[T]) FieldBySQLName(sqlName string) *RegisteredStructField {
 	for _, p := range r.Fields {
 		if p.Opts.KeyName == sqlName {
@@ -10,6 +12,9 @@ func (r *RegisteredStruct[T]) FieldBySQLName(sqlName string) *RegisteredStructFi
 	return nil
 }
 
+// FieldByGoName finds a field by its Go name. Fields of embedded and inline structs are named
+// by their dotted path, such as Audit.CreatedAt; fields promoted from anonymous embedded
+// structs are also found by their own name.
 func (r *RegisteredStruct[T]) FieldByGoName(goName string) *RegisteredStructField {
 	for _, p := range r.Fields {
 		if p.RealName == goName {
@@ -17,5 +22,13 @@ func (r *RegisteredStruct[T]) FieldByGoName(goName string) *RegisteredStructFiel
 		}
 	}
 
+	if promoted, ok := r.Type.FieldByName(goName); ok {
+		for _, p := range r.Fields {
+			if slices.Equal(p.Index, promoted.Index) {
+				return &p
+			}
+		}
+	}
+
 	return nil
 }
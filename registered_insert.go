@@ -1,41 +1,220 @@
 package gomysql
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 )
 
+// Insert adds item as a new row. It fails if the row conflicts with an existing primary key
+// or unique column.
 func (r *RegisteredStruct[T]) Insert(item *T) error {
+	return r.InsertCtx(context.Background(), item)
+}
+
+func (r *RegisteredStruct[T]) InsertCtx(ctx context.Context, item *T) error {
 	if r.db == nil {
 		return ErrDatabaseNotInitialized
 	}
 
+	var elem = reflect.ValueOf(item).Elem()
+
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	values, err := r.insertValues(ctx, r.executor(), elem, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.execInsert(ctx, r.executor(), elem, r.insertSQL, values)
+	return err
+}
+
+// InsertOrIgnore adds item unless it conflicts with an existing row, in which case the table
+// is left untouched. It reports whether the row was inserted.
+func (r *RegisteredStruct[T]) InsertOrIgnore(item *T) (inserted bool, err error) {
+	return r.InsertOrIgnoreCtx(context.Background(), item)
+}
+
+func (r *RegisteredStruct[T]) InsertOrIgnoreCtx(ctx context.Context, item *T) (inserted bool, err error) {
+	if r.db == nil {
+		return false, ErrDatabaseNotInitialized
+	}
+
+	var elem = reflect.ValueOf(item).Elem()
+
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer release()
+
+	values, err := r.insertValues(ctx, r.executor(), elem, nil)
+	if err != nil {
+		return false, err
+	}
+
+	return r.execInsert(ctx, r.executor(), elem, r.insertOrIgnoreSQL, values)
+}
+
+// Upsert inserts item, or updates the row that already has the same values in conflictFields.
+// conflictFields must be covered by the primary key or a unique constraint. Only updateFields
+// are overwritten on conflict; when updateFields is empty every inserted column outside
+// conflictFields is. Upsert reports whether the row was inserted rather than updated, and
+// fills an auto-increment primary key in both cases.
+func (r *RegisteredStruct[T]) Upsert(item *T, conflictFields []*RegisteredStructField, updateFields ...*RegisteredStructField) (inserted bool, err error) {
+	return r.UpsertCtx(context.Background(), item, conflictFields, updateFields...)
+}
+
+func (r *RegisteredStruct[T]) UpsertCtx(ctx context.Context, item *T, conflictFields []*RegisteredStructField, updateFields ...*RegisteredStructField) (inserted bool, err error) {
+	if r.db == nil {
+		return false, ErrDatabaseNotInitialized
+	}
+
+	if len(conflictFields) == 0 {
+		return false, fmt.Errorf("upsert %s: no conflict fields", r.Name)
+	}
+
 	var (
-		values []any
-		elem   = reflect.ValueOf(item).Elem()
+		d               = r.dialect()
+		elem            = reflect.ValueOf(item).Elem()
+		conflictColumns []string
+		conflictArgs    []any
+		conditions      []string
+		updateColumns   []string
 	)
 
-	r.db.lock.Lock()
-	defer r.db.lock.Unlock()
+	for _, field := range conflictFields {
+		if field == nil {
+			return false, fmt.Errorf("upsert %s: nil conflict field", r.Name)
+		}
+
+		val, err := getSQLValueOf(*field, elem.FieldByIndex(field.Index))
+		if err != nil {
+			return false, fmt.Errorf("value conversion %s: %w", field.Opts.KeyName, err)
+		}
+
+		conflictColumns = append(conflictColumns, r.quotedColumn(*field))
+		conflictArgs = append(conflictArgs, val)
+		conditions = append(conditions, r.quotedColumn(*field)+" = ?")
+	}
+
+	if len(updateFields) == 0 {
+		for _, field := range r.insertOrdered {
+			if field.Opts.PrimaryKey || containsField(conflictFields, field) {
+				continue
+			}
+			updateColumns = append(updateColumns, r.quotedColumn(field))
+		}
+	} else {
+		for _, field := range updateFields {
+			if field == nil {
+				return false, fmt.Errorf("upsert %s: nil update field", r.Name)
+			}
+			updateColumns = append(updateColumns, r.quotedColumn(*field))
+		}
+	}
+
+	var (
+		upsertSQL = d.upsertSQL(r.quotedName(), r.insertColumns(), conflictColumns, updateColumns)
+		existsSQL = fmt.Sprintf("SELECT %s FROM %s WHERE %s;", r.quotedColumn(r.PrimaryKeyField), r.quotedName(), strings.Join(conditions, " AND "))
+	)
+
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer release()
+
+	// The existence check and the upsert share a transaction so the reported outcome matches
+	// what the statement did, and the key of an updated row can be read back.
+	err = r.inTx(ctx, func(tx *Tx) error {
+		var (
+			q           = tx.executor()
+			existingKey = reflect.New(r.PrimaryKeyField.Type)
+		)
+
+		switch err := q.QueryRowContext(ctx, existsSQL, conflictArgs...).Scan(existingKey.Interface()); {
+		case errors.Is(err, sql.ErrNoRows):
+			inserted = true
+		case err != nil:
+			return fmt.Errorf("upsert lookup %s: %w", r.Name, err)
+		}
+
+		values, err := r.insertValues(ctx, q, elem, nil)
+		if err != nil {
+			return err
+		}
+
+		if inserted {
+			_, err = r.execInsert(ctx, q, elem, upsertSQL, values)
+			return err
+		}
+
+		if _, err := q.ExecContext(ctx, upsertSQL, values...); err != nil {
+			return fmt.Errorf("upsert fail %s: %w", r.Name, err)
+		}
+
+		if key := elem.FieldByIndex(r.PrimaryKeyField.Index); r.PrimaryKeyField.Opts.AutoIncr && key.IsZero() {
+			key.Set(existingKey.Elem())
+		}
+
+		return nil
+	})
+
+	return inserted, err
+}
+
+func containsField(fields []*RegisteredStructField, field RegisteredStructField) bool {
+	for _, candidate := range fields {
+		if candidate != nil && candidate.Opts.KeyName == field.Opts.KeyName {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *RegisteredStruct[T]) insertColumns() []string {
+	columns := make([]string, 0, len(r.insertOrdered))
+	for _, field := range r.insertOrdered {
+		columns = append(columns, r.quotedColumn(field))
+	}
+	return columns
+}
+
+// insertValues converts the insertable fields of elem, assigning the next value to zero-valued
+// auto-increment columns that are not the primary key. Batches pass counters so items that
+// are not yet inserted do not receive the same value.
+func (r *RegisteredStruct[T]) insertValues(ctx context.Context, q sqlExecutor, elem reflect.Value, counters map[string]int64) ([]any, error) {
+	var values []any
 
 	for _, field := range r.insertOrdered {
 		fieldValue := elem.FieldByIndex(field.Index)
 		if field.Opts.AutoIncr && !field.Opts.PrimaryKey && fieldValue.IsZero() {
-			nextValue, err := r.nextAutoIncrementValue(field)
-			if err != nil {
-				return err
+			nextValue, ok := counters[field.Opts.KeyName]
+			if !ok {
+				var err error
+				if nextValue, err = r.nextAutoIncrementValue(ctx, q, field); err != nil {
+					return nil, err
+				}
+			}
+			if counters != nil {
+				counters[field.Opts.KeyName] = nextValue + 1
 			}
 			target := fieldValue
 			if fieldValue.Kind() == reflect.Pointer {
 				target = reflect.New(fieldValue.Type().Elem()).Elem()
 			}
-			switch target.Kind() {
-			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-				target.SetInt(nextValue)
-			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-				target.SetUint(uint64(nextValue))
-			default:
-				return fmt.Errorf("auto-increment unsupported type %s for %s", target.Kind(), field.Opts.KeyName)
+			if err := setInteger(target, reflect.ValueOf(nextValue)); err != nil {
+				return nil, fmt.Errorf("auto-increment %s: %w", field.Opts.KeyName, err)
 			}
 			if fieldValue.Kind() == reflect.Pointer {
 				ptr := reflect.New(fieldValue.Type().Elem())
@@ -45,36 +224,78 @@ func (r *RegisteredStruct[T]) Insert(item *T) error {
 		}
 
 		if val, err := getSQLValueOf(field, fieldValue); err != nil {
-			return fmt.Errorf("value conversion %s: %w", field.Opts.KeyName, err)
+			return nil, fmt.Errorf("value conversion %s: %w", field.Opts.KeyName, err)
 		} else {
 			values = append(values, val)
 		}
 	}
 
-	if result, err := r.db.db.Exec(r.insertSQL, values...); err != nil {
-		return fmt.Errorf("insert fail %s: %w", r.Name, err)
-	} else if field := r.PrimaryKeyField; field.Opts.PrimaryKey && field.Opts.AutoIncr {
+	return values, nil
+}
+
+// execInsert runs an insert statement and back-fills an auto-increment primary key, through
+// RETURNING on dialects without LastInsertId. It reports whether a row was written, which is
+// false when an INSERT OR IGNORE style statement skipped a conflicting row.
+func (r *RegisteredStruct[T]) execInsert(ctx context.Context, q sqlExecutor, elem reflect.Value, query string, values []any) (bool, error) {
+	var field = r.PrimaryKeyField
+
+	if field.Opts.AutoIncr && !r.dialect().supportsLastInsertID() {
+		var insertedID int64
+		query = strings.TrimSuffix(query, ";") + " RETURNING " + r.quotedColumn(field) + ";"
+		if err := q.QueryRowContext(ctx, query, values...).Scan(&insertedID); errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		} else if err != nil {
+			return false, fmt.Errorf("insert fail %s: %w", r.Name, err)
+		}
+		if err := r.assignAutoIncrementKey(elem, insertedID); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
+	result, err := q.ExecContext(ctx, query, values...)
+	if err != nil {
+		return false, fmt.Errorf("insert fail %s: %w", r.Name, err)
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return false, nil
+	}
+
+	if field.Opts.AutoIncr {
 		if lastInsertID, err := result.LastInsertId(); err != nil {
-			return fmt.Errorf("auto-incr ID fail %s: %w", r.Name, err)
-		} else {
-			fieldValue := elem.FieldByIndex(field.Index)
-			if fieldValue.Kind() == reflect.Pointer {
-				ptr := reflect.New(fieldValue.Type().Elem())
-				ptr.Elem().SetInt(lastInsertID)
-				fieldValue.Set(ptr)
-			} else {
-				fieldValue.SetInt(lastInsertID)
-			}
+			return false, fmt.Errorf("auto-incr ID fail %s: %w", r.Name, err)
+		} else if err := r.assignAutoIncrementKey(elem, lastInsertID); err != nil {
+			return false, err
 		}
 	}
 
+	return true, nil
+}
+
+// assignAutoIncrementKey stores a generated key in the primary key field of any integer type,
+// failing when the key does not fit it.
+func (r *RegisteredStruct[T]) assignAutoIncrementKey(elem reflect.Value, id int64) error {
+	fieldValue := elem.FieldByIndex(r.PrimaryKeyField.Index)
+	target := fieldValue
+	if fieldValue.Kind() == reflect.Pointer {
+		target = reflect.New(fieldValue.Type().Elem()).Elem()
+	}
+
+	if err := setInteger(target, reflect.ValueOf(id)); err != nil {
+		return fmt.Errorf("auto-incr ID %s: %w", r.Name, err)
+	}
+
+	if fieldValue.Kind() == reflect.Pointer {
+		fieldValue.Set(target.Addr())
+	}
 	return nil
 }
 
-func (r *RegisteredStruct[T]) nextAutoIncrementValue(field RegisteredStructField) (int64, error) {
-	query := fmt.Sprintf("SELECT COALESCE(MAX(%s), 0) + 1 FROM %s;", field.Opts.KeyName, r.Name)
+func (r *RegisteredStruct[T]) nextAutoIncrementValue(ctx context.Context, q sqlExecutor, field RegisteredStructField) (int64, error) {
+	query := fmt.Sprintf("SELECT COALESCE(MAX(%s), 0) + 1 FROM %s;", r.quotedColumn(field), r.quotedName())
 	var next int64
-	if err := r.db.db.QueryRow(query).Scan(&next); err != nil {
+	if err := q.QueryRowContext(ctx, query).Scan(&next); err != nil {
 		return 0, fmt.Errorf("auto-increment query %s: %w", field.Opts.KeyName, err)
 	}
 	return next, nil
@@ -0,0 +1,317 @@
+package gomysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+func (e InsertItemError) Error() string {
+	return fmt.Sprintf("item %d: %v", e.Index, e.Err)
+}
+
+func (e InsertItemError) Unwrap() error {
+	return e.Err
+}
+
+func (e *InsertManyError) Error() string {
+	if len(e.Items) == 1 {
+		return "insert many: " + e.Items[0].Error()
+	}
+
+	return fmt.Sprintf("insert many: %d items failed, first %s", len(e.Items), e.Items[0].Error())
+}
+
+func (e *InsertManyError) Unwrap() []error {
+	errs := make([]error, len(e.Items))
+	for i, item := range e.Items {
+		errs[i] = item
+	}
+	return errs
+}
+
+// InsertMany inserts items in one transaction using multi-row INSERT statements. Like Insert
+// it fails on conflicts. Auto-increment keys are filled in once the rows are committed, and
+// inside a Tx reset if the transaction or savepoint rolls back.
+// Failures are reported per item in an *InsertManyError; see InsertManyOptions for whether
+// the remaining items are kept.
+func (r *RegisteredStruct[T]) InsertMany(items []*T, opts InsertManyOptions) error {
+	return r.InsertManyCtx(context.Background(), items, opts)
+}
+
+func (r *RegisteredStruct[T]) InsertManyCtx(ctx context.Context, items []*T, opts InsertManyOptions) error {
+	if r.db == nil {
+		return ErrDatabaseNotInitialized
+	}
+
+	if opts.BatchSize < 0 {
+		return fmt.Errorf("insert many %s: batch size must not be negative", r.Name)
+	}
+
+	if len(items) == 0 {
+		return nil
+	}
+
+	var (
+		batchSize = opts.BatchSize
+		keys      = make([]int64, len(items))
+		failed    []InsertItemError
+	)
+
+	if limit := r.dialect().maxBindParams() / len(r.insertOrdered); batchSize == 0 || batchSize > limit {
+		batchSize = limit
+	}
+
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	err = r.inTx(ctx, func(tx *Tx) error {
+		var (
+			q        = tx.executor()
+			counters = make(map[string]int64)
+			stmts    = make(map[int]*sql.Stmt)
+			pending  []int
+			rows     [][]any
+		)
+
+		defer func() {
+			for _, stmt := range stmts {
+				stmt.Close()
+			}
+		}()
+
+		fail := func(index int, err error) error {
+			failed = append(failed, InsertItemError{Index: index, Err: err})
+			if opts.ContinueOnError {
+				return nil
+			}
+			return &InsertManyError{Items: failed}
+		}
+
+		for i, item := range items {
+			if item == nil {
+				failed = append(failed, InsertItemError{Index: i, Err: errors.New("nil item")})
+				continue
+			}
+
+			values, err := r.insertValues(ctx, q, reflect.ValueOf(item).Elem(), counters)
+			if err != nil {
+				failed = append(failed, InsertItemError{Index: i, Err: err})
+				continue
+			}
+
+			pending = append(pending, i)
+			rows = append(rows, values)
+		}
+
+		if len(failed) > 0 && !opts.ContinueOnError {
+			return &InsertManyError{Items: failed}
+		}
+
+		for start := 0; start < len(rows); start += batchSize {
+			end := min(start+batchSize, len(rows))
+
+			chunkKeys, err := r.insertChunk(ctx, tx, stmts, rows[start:end])
+			if err == nil {
+				for j, key := range chunkKeys {
+					keys[pending[start+j]] = key
+				}
+				continue
+			}
+
+			if ctx.Err() != nil {
+				return err
+			}
+
+			// Retry the chunk row by row to find the items that caused the failure.
+			for j := start; j < end; j++ {
+				rowKeys, err := r.insertChunk(ctx, tx, stmts, rows[j:j+1])
+				if err != nil {
+					if err := fail(pending[j], err); err != nil {
+						return err
+					}
+					continue
+				}
+				keys[pending[j]] = rowKeys[0]
+			}
+		}
+
+		return nil
+	})
+
+	slices.SortFunc(failed, func(a, b InsertItemError) int {
+		return a.Index - b.Index
+	})
+
+	if err != nil {
+		var manyErr *InsertManyError
+		if errors.As(err, &manyErr) {
+			manyErr.Items = failed
+		}
+		return err
+	}
+
+	// Keys are filled in once the rows are committed, or inside a transaction reset to their
+	// previous values if it rolls back.
+	if r.PrimaryKeyField.Opts.AutoIncr {
+		for i, item := range items {
+			if keys[i] == 0 {
+				continue
+			}
+
+			elem := reflect.ValueOf(item).Elem()
+			key := elem.FieldByIndex(r.PrimaryKeyField.Index)
+			previous := reflect.New(key.Type()).Elem()
+			previous.Set(key)
+
+			if err := r.assignAutoIncrementKey(elem, keys[i]); err != nil {
+				return err
+			}
+
+			if r.tx != nil {
+				r.tx.onRollback(func() { key.Set(previous) })
+			}
+		}
+	}
+
+	if len(failed) > 0 {
+		return &InsertManyError{Items: failed}
+	}
+
+	return nil
+}
+
+// insertChunk writes rows with one multi-row INSERT inside a savepoint, so a failing chunk can
+// be retried row by row. Statements are prepared once per row count and reused through stmts.
+// It returns the auto-increment keys of the rows in order.
+func (r *RegisteredStruct[T]) insertChunk(ctx context.Context, tx *Tx, stmts map[int]*sql.Stmt, rows [][]any) (keys []int64, err error) {
+	var (
+		d       = r.dialect()
+		autoKey = r.PrimaryKeyField.Opts.AutoIncr
+		args    = make([]any, 0, len(rows)*len(r.insertOrdered))
+	)
+
+	for _, row := range rows {
+		args = append(args, row...)
+	}
+	args = dialectExecutor{dialect: d}.bindArgs(args)
+
+	prepared := func(count int) (*sql.Stmt, error) {
+		if stmt, ok := stmts[count]; ok {
+			return stmt, nil
+		}
+
+		stmt, err := tx.prepare(ctx, r.insertManySQL(count))
+		if err != nil {
+			return nil, fmt.Errorf("insert many prepare %s: %w", r.Name, err)
+		}
+		stmts[count] = stmt
+		return stmt, nil
+	}
+
+	err = tx.savepoint(ctx, func(*Tx) error {
+		if autoKey && d.supportsLastInsertID() && !d.consecutiveInsertIDs() {
+			// Keys of one multi-row insert may have gaps, so each row is inserted on its own
+			// and reports its own key.
+			stmt, err := prepared(1)
+			if err != nil {
+				return err
+			}
+
+			width := len(r.insertOrdered)
+			for i := range rows {
+				result, err := stmt.ExecContext(ctx, args[i*width:(i+1)*width]...)
+				if err != nil {
+					return fmt.Errorf("insert many fail %s: %w", r.Name, err)
+				}
+
+				key, err := result.LastInsertId()
+				if err != nil {
+					return fmt.Errorf("auto-incr ID fail %s: %w", r.Name, err)
+				}
+				keys = append(keys, key)
+			}
+			return nil
+		}
+
+		stmt, err := prepared(len(rows))
+		if err != nil {
+			return err
+		}
+
+		if autoKey && !d.supportsLastInsertID() {
+			result, err := stmt.QueryContext(ctx, args...)
+			if err != nil {
+				return fmt.Errorf("insert many fail %s: %w", r.Name, err)
+			}
+			defer result.Close()
+
+			for result.Next() {
+				var key int64
+				if err := result.Scan(&key); err != nil {
+					return fmt.Errorf("insert many scan %s: %w", r.Name, err)
+				}
+				keys = append(keys, key)
+			}
+
+			if err := result.Err(); err != nil {
+				return fmt.Errorf("insert many fail %s: %w", r.Name, err)
+			}
+
+			if len(keys) != len(rows) {
+				return fmt.Errorf("insert many %s: expected %d keys, got %d", r.Name, len(rows), len(keys))
+			}
+			return nil
+		}
+
+		result, err := stmt.ExecContext(ctx, args...)
+		if err != nil {
+			return fmt.Errorf("insert many fail %s: %w", r.Name, err)
+		}
+
+		if !autoKey {
+			return nil
+		}
+
+		lastInsertID, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("auto-incr ID fail %s: %w", r.Name, err)
+		}
+
+		// The dialect keeps the keys of one insert consecutive, ending with the reported one.
+		first := lastInsertID - int64(len(rows)) + 1
+		for i := range rows {
+			keys = append(keys, first+int64(i))
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// INSERT INTO X (key2, ...) VALUES (?, ...), (?, ...), ...;
+// with RETURNING key1 on dialects without LastInsertId.
+func (r *RegisteredStruct[T]) insertManySQL(rows int) string {
+	var (
+		row    = "(" + placeholders(len(r.insertOrdered)) + ")"
+		values = strings.TrimSuffix(strings.Repeat(row+", ", rows), ", ")
+		query  = fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", r.quotedName(), joinColumns(r.insertColumns()), values)
+	)
+
+	if r.PrimaryKeyField.Opts.AutoIncr && !r.dialect().supportsLastInsertID() {
+		query += " RETURNING " + r.quotedColumn(r.PrimaryKeyField)
+	}
+
+	return query + ";"
+}
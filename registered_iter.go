@@ -0,0 +1,154 @@
+package gomysql
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"reflect"
+)
+
+// iterBatchSize is the number of rows Iter reads per query when it streams in batches.
+const iterBatchSize = 500
+
+// Iter streams the rows matching filter, or every row for a nil filter, decoding one row per
+// step instead of collecting them, so memory stays bounded on large tables. An error ends the
+// sequence as its last pair. Each yielded *T is a fresh value that may be kept.
+//
+// Without a read pool or transaction, a single query would hold the driver's read lock, and on
+// SQLite its only connection, for the whole loop. Iter instead reads the rows in batches sorted
+// by the filter's ordering and the primary key, releasing the driver between batches, so the
+// loop body may read and write through the driver. Rows written during the loop are seen when
+// they sort after the current row. A filter with a limit, an offset or a nullable ordering field
+// is read in one query before the first row is yielded. A driver opened with ReadPoolSize, or a
+// view bound to a transaction, streams every row from one query.
+func (r *RegisteredStruct[T]) Iter(filter *Filter) iter.Seq2[*T, error] {
+	return r.IterCtx(context.Background(), filter)
+}
+
+func (r *RegisteredStruct[T]) IterCtx(ctx context.Context, filter *Filter) iter.Seq2[*T, error] {
+	return func(yield func(*T, error) bool) {
+		if err := r.iterate(ctx, filter, func(item *T) bool {
+			return yield(item, nil)
+		}); err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+// ForEach calls fn for each row matching filter, or every row for a nil filter, reading them
+// like Iter. It stops at and returns the first error from fn.
+func (r *RegisteredStruct[T]) ForEach(filter *Filter, fn func(item *T) error) error {
+	return r.ForEachCtx(context.Background(), filter, fn)
+}
+
+func (r *RegisteredStruct[T]) ForEachCtx(ctx context.Context, filter *Filter, fn func(item *T) error) error {
+	var fnErr error
+	err := r.iterate(ctx, filter, func(item *T) bool {
+		fnErr = fn(item)
+		return fnErr == nil
+	})
+	if fnErr != nil {
+		return fnErr
+	}
+
+	return err
+}
+
+// iterate passes the rows matching filter to yield until it returns false, streaming them from
+// one query when that does not hold the driver's lock and reading them in batches otherwise.
+func (r *RegisteredStruct[T]) iterate(ctx context.Context, filter *Filter, yield func(*T) bool) error {
+	if r.db == nil {
+		return ErrDatabaseNotInitialized
+	}
+
+	if r.tx != nil || r.db.readStmts != nil || r.db.readDB != nil {
+		query, args, err := r.selectAllQuery(filter)
+		if err != nil {
+			return err
+		}
+		return r.streamRows(ctx, query, args, yield)
+	}
+
+	var order []PageOrder
+	if filter != nil {
+		if filter.limitClause != "" || filter.offsetClause != "" ||
+			(filter.order != nil && filter.order.Field.Type.Kind() == reflect.Pointer) {
+			return r.iterateAll(ctx, filter, yield)
+		}
+
+		if filter.order != nil {
+			order = []PageOrder{*filter.order}
+		}
+
+		conditions := *filter
+		conditions.orderByClause, conditions.order = "", nil
+		filter = &conditions
+	}
+
+	keys, err := r.pageKeys(order)
+	if err != nil {
+		return err
+	}
+
+	var after []any
+	for {
+		batch, _, err := r.pageFilter(filter, keys)
+		if err != nil {
+			return err
+		}
+
+		if after != nil {
+			appendKeysetCondition(batch, keys, after, false)
+		}
+		batch.orderByClause = keysetOrderBy(keys, false)
+		batch.limitClause = fmt.Sprintf("LIMIT %d", iterBatchSize)
+
+		query, args, err := r.selectAllQuery(batch)
+		if err != nil {
+			return err
+		}
+
+		items, err := r.selectAll(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			if !yield(item) {
+				return nil
+			}
+		}
+
+		if len(items) < iterBatchSize {
+			return nil
+		}
+
+		last := reflect.ValueOf(items[len(items)-1]).Elem()
+		after = make([]any, len(keys))
+		for i, key := range keys {
+			after[i] = last.FieldByIndex(key.Field.Index).Interface()
+		}
+	}
+}
+
+// iterateAll reads every row matching filter with one query, releasing the driver before the
+// rows are passed to yield.
+func (r *RegisteredStruct[T]) iterateAll(ctx context.Context, filter *Filter, yield func(*T) bool) error {
+	query, args, err := r.selectAllQuery(filter)
+	if err != nil {
+		return err
+	}
+
+	items, err := r.selectAll(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if !yield(item) {
+			return nil
+		}
+	}
+
+	return nil
+}
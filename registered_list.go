@@ -1,29 +1,172 @@
 package gomysql
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+)
 
+// List returns the primary keys of every row as the values the database driver scanned, such
+// as int64 for integer keys. Composite keys are []any tuples in key order. ListWithFilter and
+// ListKeys return keys of their declared Go types instead.
 func (r *RegisteredStruct[T]) List() ([]any, error) {
+	return r.ListCtx(context.Background())
+}
+
+func (r *RegisteredStruct[T]) ListCtx(ctx context.Context) ([]any, error) {
 	if r.db == nil {
 		return nil, ErrDatabaseNotInitialized
 	}
 
-	r.db.lock.Lock()
-	defer r.db.lock.Unlock()
+	q, release, err := r.readExecutor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
 
-	rows, err := r.db.db.Query(r.listSQL)
+	rows, err := q.QueryContext(ctx, r.listSQL)
 	if err != nil {
 		return nil, fmt.Errorf("list fail %s: %w", r.Name, err)
 	}
 	defer rows.Close()
 
-	var keys []any
+	var (
+		keys     []any
+		tuple    = make([]any, len(r.PrimaryKeyFields))
+		scanArgs = make([]any, len(tuple))
+	)
+
+	for i := range tuple {
+		scanArgs[i] = &tuple[i]
+	}
+
 	for rows.Next() {
-		var key any
-		if err := rows.Scan(&key); err != nil {
+		if err := rows.Scan(scanArgs...); err != nil {
 			return nil, fmt.Errorf("scan fail %s: %w", r.Name, err)
 		}
+
+		if len(tuple) == 1 {
+			keys = append(keys, tuple[0])
+		} else {
+			keys = append(keys, slices.Clone(tuple))
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error for %s: %w", r.Name, err)
+	}
+
+	return keys, nil
+}
+
+// ListWithFilter returns the primary keys of the rows matching filter, or of every row for a
+// nil filter, decoded into the key fields' Go types. Composite keys are []any tuples in key
+// order.
+func (r *RegisteredStruct[T]) ListWithFilter(filter *Filter) ([]any, error) {
+	return r.ListWithFilterCtx(context.Background(), filter)
+}
+
+func (r *RegisteredStruct[T]) ListWithFilterCtx(ctx context.Context, filter *Filter) ([]any, error) {
+	var keys []any
+	err := r.listKeys(ctx, filter, func(tuple []reflect.Value) error {
+		if len(tuple) == 1 {
+			keys = append(keys, tuple[0].Interface())
+			return nil
+		}
+
+		values := make([]any, len(tuple))
+		for i, value := range tuple {
+			values[i] = value.Interface()
+		}
+		keys = append(keys, values)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// ListKeys returns the primary keys of the rows matching filter, or of every row for a nil
+// filter, as K. For a single-column key, K is the key field's type or one it converts to. For
+// a composite key, K is T, a struct with fields named like the key fields, or []any.
+func ListKeys[K, T any](r *RegisteredStruct[T], filter *Filter) ([]K, error) {
+	return ListKeysCtx[K](context.Background(), r, filter)
+}
+
+func ListKeysCtx[K, T any](ctx context.Context, r *RegisteredStruct[T], filter *Filter) ([]K, error) {
+	target := reflect.TypeFor[K]()
+	if len(r.PrimaryKeyFields) == 1 && !decodableAs(r.PrimaryKeyField.Type, target) {
+		return nil, fmt.Errorf("cannot list keys of %s of type %s as %s", r.Name, r.PrimaryKeyField.Type, target)
+	}
+
+	if len(r.PrimaryKeyFields) > 1 && target.Kind() != reflect.Struct && target != reflect.TypeFor[[]any]() {
+		return nil, fmt.Errorf("composite keys of %s must be listed as a struct or []any, got %s", r.Name, target)
+	}
+
+	var keys []K
+	err := r.listKeys(ctx, filter, func(tuple []reflect.Value) error {
+		var key K
+		out := reflect.ValueOf(&key).Elem()
+
+		switch {
+		case len(tuple) == 1:
+			if err := setDecoded(out, tuple[0]); err != nil {
+				return fmt.Errorf("list keys %s: %w", r.Name, err)
+			}
+		case target.Kind() == reflect.Slice:
+			values := make([]any, len(tuple))
+			for i, value := range tuple {
+				values[i] = value.Interface()
+			}
+			out.Set(reflect.ValueOf(values))
+		default:
+			for i, field := range r.PrimaryKeyFields {
+				var fieldValue reflect.Value
+				if target == r.Type {
+					fieldValue = out.FieldByIndex(field.Index)
+				} else if fieldValue = fieldByGoPath(out, field.RealName); !fieldValue.IsValid() || !fieldValue.CanSet() {
+					return fmt.Errorf("key struct %s has no field %s", target, field.RealName)
+				}
+
+				if !decodableAs(field.Type, fieldValue.Type()) {
+					return fmt.Errorf("cannot list key field %s of type %s as %s", field.RealName, field.Type, fieldValue.Type())
+				}
+
+				if err := setDecoded(fieldValue, tuple[i]); err != nil {
+					return fmt.Errorf("list keys %s: %w", r.Name, err)
+				}
+			}
+		}
+
 		keys = append(keys, key)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return keys, nil
 }
+
+// listKeys passes the primary key of each row matching filter to fn, decoded into the key
+// fields' types.
+func (r *RegisteredStruct[T]) listKeys(ctx context.Context, filter *Filter, fn func(tuple []reflect.Value) error) error {
+	fields := make([]*RegisteredStructField, len(r.PrimaryKeyFields))
+	for i := range r.PrimaryKeyFields {
+		fields[i] = &r.PrimaryKeyFields[i]
+	}
+
+	return r.queryFields(ctx, filter, fields, func(values []any) error {
+		tuple := make([]reflect.Value, len(fields))
+		for i, field := range fields {
+			tuple[i] = reflect.New(field.Type).Elem()
+			if err := assignDecodedValue(tuple[i], *field, values[i]); err != nil {
+				return err
+			}
+		}
+		return fn(tuple)
+	})
+}
@@ -0,0 +1,283 @@
+package gomysql
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// ErrInvalidCursor is returned by Paginate for a cursor that was altered, signed with another
+// key or issued for a different filter or sort order.
+var ErrInvalidCursor = errors.New("invalid pagination cursor")
+
+// PageOrder is one column of the sort order of Paginate.
+type PageOrder struct {
+	Field *RegisteredStructField
+	Desc  bool
+}
+
+// Page is one page of rows returned by Paginate. Next and Prev are cursors that load the
+// adjacent pages when passed back to Paginate with the same filter and sort order.
+type Page[T any] struct {
+	Items []*T
+	Next  string // empty on the last page
+	Prev  string // empty on the first page
+}
+
+// pageCursor is the signed content of a cursor: the sort key of the row it continues from.
+type pageCursor struct {
+	Backward bool
+	Values   [][]byte // gob-encoded field values, in sort key order
+}
+
+// Paginate returns up to pageSize rows matching filter, or every row for a nil filter, sorted by
+// order and then by the primary key, starting after the row encoded in cursor. An empty cursor
+// loads the first page. Rows are found by comparing sort keys instead of skipping with OFFSET,
+// so deep pages cost the same as the first when the sort columns are indexed.
+//
+// Cursors are signed with DriverOptions.CursorKey, so altered cursors and cursors of another
+// query fail with ErrInvalidCursor. They are not encrypted. The filter must not set ordering,
+// limit or offset, and sort fields must not be pointers, since NULL sort keys cannot be compared.
+func (r *RegisteredStruct[T]) Paginate(filter *Filter, order []PageOrder, pageSize int, cursor string) (*Page[T], error) {
+	return r.PaginateCtx(context.Background(), filter, order, pageSize, cursor)
+}
+
+func (r *RegisteredStruct[T]) PaginateCtx(ctx context.Context, filter *Filter, order []PageOrder, pageSize int, cursor string) (*Page[T], error) {
+	if r.db == nil {
+		return nil, ErrDatabaseNotInitialized
+	}
+
+	if pageSize <= 0 {
+		return nil, fmt.Errorf("page size must be positive, got %d", pageSize)
+	}
+
+	keys, err := r.pageKeys(order)
+	if err != nil {
+		return nil, err
+	}
+
+	page, scope, err := r.pageFilter(filter, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	var from pageCursor
+	if cursor != "" {
+		var values []any
+		if from, values, err = r.decodeCursor(cursor, scope, keys); err != nil {
+			return nil, err
+		}
+		appendKeysetCondition(page, keys, values, from.Backward)
+	}
+
+	page.orderByClause = keysetOrderBy(keys, from.Backward)
+	page.limitClause = fmt.Sprintf("LIMIT %d", pageSize+1)
+
+	query, args, err := r.selectAllQuery(page)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := r.selectAll(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	more := len(items) > pageSize
+	if more {
+		items = items[:pageSize]
+	}
+	if from.Backward {
+		slices.Reverse(items)
+	}
+
+	result := &Page[T]{Items: items}
+	if len(items) == 0 {
+		return result, nil
+	}
+
+	// Coming back from a later page means there is one; going forward from a cursor means
+	// there is an earlier one.
+	if more || from.Backward {
+		if result.Next, err = r.encodeCursor(items[len(items)-1], false, scope, keys); err != nil {
+			return nil, err
+		}
+	}
+	if (more && from.Backward) || (cursor != "" && !from.Backward) {
+		if result.Prev, err = r.encodeCursor(items[0], true, scope, keys); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// pageKeys checks the sort order and completes it with the primary key columns it lacks, so
+// every row has a distinct sort key.
+func (r *RegisteredStruct[T]) pageKeys(order []PageOrder) ([]PageOrder, error) {
+	var keys []PageOrder
+	for _, key := range order {
+		if key.Field == nil || r.FieldBySQLName(key.Field.Opts.KeyName) == nil {
+			return nil, fmt.Errorf("Paginate requires fields of %s", r.Name)
+		}
+
+		if key.Field.Type.Kind() == reflect.Pointer {
+			return nil, fmt.Errorf("Paginate cannot sort by nullable field %s", key.Field.Opts.KeyName)
+		}
+
+		if slices.ContainsFunc(keys, func(k PageOrder) bool { return k.Field.Opts.KeyName == key.Field.Opts.KeyName }) {
+			return nil, fmt.Errorf("Paginate sorts by field %s twice", key.Field.Opts.KeyName)
+		}
+
+		keys = append(keys, key)
+	}
+
+	for i := range r.PrimaryKeyFields {
+		field := &r.PrimaryKeyFields[i]
+		if !slices.ContainsFunc(keys, func(k PageOrder) bool { return k.Field.Opts.KeyName == field.Opts.KeyName }) {
+			keys = append(keys, PageOrder{Field: field})
+		}
+	}
+
+	return keys, nil
+}
+
+// pageFilter copies the conditions of filter into a new filter that the page's keyset condition
+// and ordering are added to. scope identifies the query so cursors only apply to it.
+func (r *RegisteredStruct[T]) pageFilter(filter *Filter, keys []PageOrder) (page *Filter, scope string, err error) {
+	page = NewFilter()
+	scope = r.Name
+
+	for _, key := range keys {
+		scope += fmt.Sprintf("\x00%s %t", key.Field.Opts.KeyName, key.Desc)
+	}
+
+	if filter == nil {
+		return page, scope, nil
+	}
+
+	if filter.orderByClause != "" || filter.limitClause != "" || filter.offsetClause != "" {
+		return nil, "", fmt.Errorf("Paginate filter must not set ordering, limit or offset")
+	}
+
+	where, args, err := filter.build(r.dialect())
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to build filter: %w", err)
+	}
+	scope += fmt.Sprintf("\x00%s\x00%v", where, args)
+
+	if len(filter.whereTokens) > 0 {
+		page.whereTokens = append(append([]string{"("}, filter.whereTokens...), ")")
+		page.args = slices.Clone(filter.args)
+		page.lastWasJoiner = false
+	}
+
+	return page, scope, nil
+}
+
+// appendKeysetCondition restricts page to the rows after the cursor's row in sort order, or
+// before it for a backward cursor: (a > ?) OR (a = ? AND b > ?) OR ...
+func appendKeysetCondition(page *Filter, keys []PageOrder, values []any, backward bool) {
+	if len(page.whereTokens) > 0 {
+		page.And()
+	}
+
+	page.OpenGroup()
+	for i, key := range keys {
+		if i > 0 {
+			page.Or()
+		}
+
+		page.OpenGroup()
+		for j := range i {
+			page.KeyCmp(keys[j].Field, OpEqual, values[j]).And()
+		}
+
+		op := OpGreaterThan
+		if key.Desc != backward {
+			op = OpLessThan
+		}
+		page.KeyCmp(key.Field, op, values[i]).CloseGroup()
+	}
+	page.CloseGroup()
+}
+
+// keysetOrderBy sorts by keys, reversing every direction for a backward cursor.
+func keysetOrderBy(keys []PageOrder, backward bool) string {
+	orderBy := make([]string, len(keys))
+	for i, key := range keys {
+		dir := "ASC"
+		if key.Desc != backward {
+			dir = "DESC"
+		}
+		orderBy[i] = markIdent(key.Field.Opts.KeyName) + " " + dir
+	}
+	return "ORDER BY " + strings.Join(orderBy, ", ")
+}
+
+func (r *RegisteredStruct[T]) encodeCursor(item *T, backward bool, scope string, keys []PageOrder) (string, error) {
+	var (
+		elem = reflect.ValueOf(item).Elem()
+		from = pageCursor{Backward: backward}
+	)
+
+	for _, key := range keys {
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).EncodeValue(elem.FieldByIndex(key.Field.Index)); err != nil {
+			return "", fmt.Errorf("encode cursor %s: %w", key.Field.Opts.KeyName, err)
+		}
+		from.Values = append(from.Values, buf.Bytes())
+	}
+
+	var payload bytes.Buffer
+	if err := gob.NewEncoder(&payload).Encode(from); err != nil {
+		return "", fmt.Errorf("encode cursor: %w", err)
+	}
+
+	token := append(payload.Bytes(), r.cursorMAC(scope, payload.Bytes())...)
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
+
+// decodeCursor verifies a cursor and decodes its sort key into values of the fields' types.
+func (r *RegisteredStruct[T]) decodeCursor(cursor, scope string, keys []PageOrder) (from pageCursor, values []any, err error) {
+	token, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || len(token) < sha256.Size {
+		return from, nil, ErrInvalidCursor
+	}
+
+	payload, mac := token[:len(token)-sha256.Size], token[len(token)-sha256.Size:]
+	if !hmac.Equal(mac, r.cursorMAC(scope, payload)) {
+		return from, nil, ErrInvalidCursor
+	}
+
+	if err = gob.NewDecoder(bytes.NewReader(payload)).Decode(&from); err != nil || len(from.Values) != len(keys) {
+		return from, nil, ErrInvalidCursor
+	}
+
+	values = make([]any, len(keys))
+	for i, key := range keys {
+		value := reflect.New(key.Field.Type)
+		if err = gob.NewDecoder(bytes.NewReader(from.Values[i])).DecodeValue(value); err != nil {
+			return from, nil, ErrInvalidCursor
+		}
+		values[i] = value.Elem().Interface()
+	}
+
+	return from, values, nil
+}
+
+func (r *RegisteredStruct[T]) cursorMAC(scope string, payload []byte) []byte {
+	mac := hmac.New(sha256.New, r.db.cursorKey)
+	mac.Write([]byte(scope))
+	mac.Write([]byte{0})
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
@@ -1,32 +1,34 @@
 package gomysql
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
-	"reflect"
 )
 
 func (r *RegisteredStruct[T]) Select(primaryKeyValue any) (item *T, err error) {
+	return r.SelectCtx(context.Background(), primaryKeyValue)
+}
+
+func (r *RegisteredStruct[T]) SelectCtx(ctx context.Context, primaryKeyValue any) (item *T, err error) {
 	if r.db == nil {
 		return nil, ErrDatabaseNotInitialized
 	}
 
-	item = new(T)
+	keyArgs, err := r.keyArgs(primaryKeyValue)
+	if err != nil {
+		return nil, err
+	}
 
-	var (
-		values   []any = make([]any, len(r.nonInsertionOrdered))
-		scanArgs []any = make([]any, len(r.nonInsertionOrdered))
-		elem           = reflect.ValueOf(item).Elem()
-	)
+	values, scanArgs := r.rowScanTargets()
 
-	for i := range r.nonInsertionOrdered {
-		scanArgs[i] = &values[i]
+	q, release, err := r.readExecutor(ctx)
+	if err != nil {
+		return nil, err
 	}
+	defer release()
 
-	r.db.lock.Lock()
-	defer r.db.lock.Unlock()
-
-	row := r.db.db.QueryRow(r.selectSQL, primaryKeyValue)
+	row := q.QueryRowContext(ctx, r.selectSQL, keyArgs...)
 	if err = row.Scan(scanArgs...); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -35,24 +37,5 @@ func (r *RegisteredStruct[T]) Select(primaryKeyValue any) (item *T, err error) {
 		return nil, fmt.Errorf("failed to select from %s: %w", r.Name, err)
 	}
 
-	for i, field := range r.nonInsertionOrdered {
-		raw := values[i]
-		fieldValue := elem.FieldByIndex(field.Index)
-		if err := assignDecodedValue(fieldValue, field, raw); err != nil {
-			return nil, err
-		}
-	}
-
-	if primaryKeyField := r.PrimaryKeyField; primaryKeyField.RealName != "" {
-		fieldValue := elem.FieldByIndex(primaryKeyField.Index)
-		if fieldValue.Kind() == reflect.Pointer {
-			target := reflect.New(fieldValue.Type().Elem())
-			target.Elem().Set(reflect.ValueOf(primaryKeyValue).Convert(fieldValue.Type().Elem()))
-			fieldValue.Set(target)
-		} else {
-			fieldValue.Set(reflect.ValueOf(primaryKeyValue).Convert(fieldValue.Type()))
-		}
-	}
-
-	return item, nil
+	return r.decodeRow(values)
 }
@@ -1,86 +1,141 @@
 package gomysql
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"strings"
 )
 
-func (r *RegisteredStruct[T]) selectAll(sql string, args ...any) ([]*T, error) {
+func (r *RegisteredStruct[T]) selectAll(ctx context.Context, sql string, args ...any) ([]*T, error) {
+	var results []*T
+	err := r.streamRows(ctx, sql, args, func(item *T) bool {
+		results = append(results, item)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// streamRows runs a query laid out as selectAllSQL and passes each decoded row to yield until
+// it returns false. The read executor, and the driver's read lock when there is no read pool or
+// transaction, is held until the rows are closed.
+func (r *RegisteredStruct[T]) streamRows(ctx context.Context, sql string, args []any, yield func(*T) bool) error {
 	if r.db == nil {
-		return nil, ErrDatabaseNotInitialized
+		return ErrDatabaseNotInitialized
 	}
 
-	r.db.lock.Lock()
-	defer r.db.lock.Unlock()
+	q, release, err := r.readExecutor(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
 
-	rows, err := r.db.db.Query(sql, args...)
+	rows, err := q.QueryContext(ctx, sql, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query all from %s: %w", r.Name, err)
+		return fmt.Errorf("failed to query all from %s: %w", r.Name, err)
 	}
 	defer rows.Close()
 
-	var results []*T
-	values := make([]any, len(r.nonInsertionOrdered)+1)
-	scanArgs := make([]any, len(values))
-	for i := range values {
-		scanArgs[i] = &values[i]
-	}
+	values, scanArgs := r.rowScanTargets()
 
 	for rows.Next() {
-		item := new(T)
-
 		if err := rows.Scan(scanArgs...); err != nil {
-			return nil, fmt.Errorf("failed to scan all from %s: %w", r.Name, err)
+			return fmt.Errorf("failed to scan all from %s: %w", r.Name, err)
 		}
 
-		elem := reflect.ValueOf(item).Elem()
-		pkValue := values[0]
-		if pkValue == nil {
-			return nil, fmt.Errorf("primary key value is nil for %s", r.Name)
+		item, err := r.decodeRow(values)
+		if err != nil {
+			return err
 		}
 
-		pkField := elem.FieldByIndex(r.PrimaryKeyField.Index)
-		if err := assignDecodedValue(pkField, r.PrimaryKeyField, pkValue); err != nil {
-			return nil, err
+		if !yield(item) {
+			return nil
 		}
+	}
 
-		for i, field := range r.nonInsertionOrdered {
-			raw := values[i+1]
-			fieldValue := elem.FieldByIndex(field.Index)
-			if err := assignDecodedValue(fieldValue, field, raw); err != nil {
-				return nil, err
-			}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("rows iteration error for %s: %w", r.Name, err)
+	}
+
+	return nil
+}
+
+// rowScanTargets allocates scan destinations for rows laid out as selectAllSQL: the primary key
+// columns followed by the remaining columns.
+func (r *RegisteredStruct[T]) rowScanTargets() (values, scanArgs []any) {
+	values = make([]any, len(r.PrimaryKeyFields)+len(r.nonInsertionOrdered))
+	scanArgs = make([]any, len(values))
+	for i := range values {
+		scanArgs[i] = &values[i]
+	}
+	return values, scanArgs
+}
+
+func (r *RegisteredStruct[T]) decodeRow(values []any) (*T, error) {
+	var (
+		item = new(T)
+		elem = reflect.ValueOf(item).Elem()
+	)
+
+	for i, field := range r.PrimaryKeyFields {
+		if values[i] == nil {
+			return nil, fmt.Errorf("primary key value is nil for %s", r.Name)
 		}
 
-		results = append(results, item)
+		if err := assignDecodedValue(elem.FieldByIndex(field.Index), field, values[i]); err != nil {
+			return nil, err
+		}
 	}
 
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("rows iteration error for %s: %w", r.Name, err)
+	for i, field := range r.nonInsertionOrdered {
+		raw := values[len(r.PrimaryKeyFields)+i]
+		if err := assignDecodedValue(elem.FieldByIndex(field.Index), field, raw); err != nil {
+			return nil, err
+		}
 	}
 
-	return results, nil
+	return item, nil
 }
 
 func (r *RegisteredStruct[T]) SelectAll() ([]*T, error) {
-	return r.selectAll(r.selectAllSQL)
+	return r.selectAll(context.Background(), r.selectAllSQL)
+}
+
+func (r *RegisteredStruct[T]) SelectAllCtx(ctx context.Context) ([]*T, error) {
+	return r.selectAll(ctx, r.selectAllSQL)
 }
 
 func (r *RegisteredStruct[T]) SelectAllWithFilter(filter *Filter) ([]*T, error) {
+	return r.SelectAllWithFilterCtx(context.Background(), filter)
+}
+
+func (r *RegisteredStruct[T]) SelectAllWithFilterCtx(ctx context.Context, filter *Filter) ([]*T, error) {
 	if r.db == nil {
 		return nil, ErrDatabaseNotInitialized
 	}
 
-	var (
-		filterString string
-		filterArgs   []any
-		err          error
-	)
+	query, args, err := r.selectAllQuery(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.selectAll(ctx, query, args...)
+}
 
-	if filterString, filterArgs, err = filter.Build(); err != nil {
-		return nil, fmt.Errorf("failed to build filter: %w", err)
+// selectAllQuery appends filter to selectAllSQL; a nil filter selects every row.
+func (r *RegisteredStruct[T]) selectAllQuery(filter *Filter) (string, []any, error) {
+	if filter == nil {
+		return r.selectAllSQL, nil, nil
+	}
+
+	filterString, filterArgs, err := filter.build(r.dialect())
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to build filter: %w", err)
 	}
 
-	return r.selectAll(fmt.Sprintf("%s %s;", r.selectAllSQL[:len(r.selectAllSQL)-1], strings.TrimSpace(filterString)), filterArgs...)
+	return fmt.Sprintf("%s %s;", r.selectAllSQL[:len(r.selectAllSQL)-1], strings.TrimSpace(filterString)), filterArgs, nil
 }
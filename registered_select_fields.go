@@ -0,0 +1,174 @@
+package gomysql
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// SelectFields returns the rows matching filter, or every row for a nil filter, reading only
+// the given columns. The other fields of the returned items, including the primary key when it
+// is not requested, are left at their zero values.
+func (r *RegisteredStruct[T]) SelectFields(filter *Filter, fields ...*RegisteredStructField) ([]*T, error) {
+	return r.SelectFieldsCtx(context.Background(), filter, fields...)
+}
+
+func (r *RegisteredStruct[T]) SelectFieldsCtx(ctx context.Context, filter *Filter, fields ...*RegisteredStructField) ([]*T, error) {
+	var results []*T
+	err := r.queryFields(ctx, filter, fields, func(values []any) error {
+		var (
+			item = new(T)
+			elem = reflect.ValueOf(item).Elem()
+		)
+
+		for i, field := range fields {
+			if err := assignDecodedValue(elem.FieldByIndex(field.Index), *field, values[i]); err != nil {
+				return err
+			}
+		}
+
+		results = append(results, item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// Pluck returns one column of the rows matching filter, or of every row for a nil filter. V
+// must be the field's type or one it converts to, such as int64 for an int field; integer
+// values that do not fit V are an error.
+func Pluck[V, T any](r *RegisteredStruct[T], field *RegisteredStructField, filter *Filter) ([]V, error) {
+	return PluckCtx[V](context.Background(), r, field, filter)
+}
+
+func PluckCtx[V, T any](ctx context.Context, r *RegisteredStruct[T], field *RegisteredStructField, filter *Filter) ([]V, error) {
+	if field == nil {
+		return nil, fmt.Errorf("Pluck requires a valid field")
+	}
+
+	if target := reflect.TypeFor[V](); !decodableAs(field.Type, target) {
+		return nil, fmt.Errorf("cannot pluck field %s of type %s as %s", field.Opts.KeyName, field.Type, target)
+	}
+
+	var results []V
+	err := r.queryFields(ctx, filter, []*RegisteredStructField{field}, func(values []any) error {
+		decoded := reflect.New(field.Type).Elem()
+		if err := assignDecodedValue(decoded, *field, values[0]); err != nil {
+			return err
+		}
+
+		var value V
+		if err := setDecoded(reflect.ValueOf(&value).Elem(), decoded); err != nil {
+			return fmt.Errorf("pluck %s: %w", field.Opts.KeyName, err)
+		}
+
+		results = append(results, value)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// decodableAs reports whether setDecoded can store values of a field type in target. Go
+// converts integers to strings as code points, which is never the intent here.
+func decodableAs(field, target reflect.Type) bool {
+	return field.AssignableTo(target) ||
+		field.ConvertibleTo(target) && (target.Kind() != reflect.String || field.Kind() == reflect.String)
+}
+
+// setDecoded stores a decoded field value in out, whose type may differ from the field's as
+// allowed by decodableAs. Integers that do not fit out are an error.
+func setDecoded(out, decoded reflect.Value) error {
+	switch {
+	case decoded.Type().AssignableTo(out.Type()):
+		out.Set(decoded)
+	case (decoded.CanInt() || decoded.CanUint()) && (out.CanInt() || out.CanUint()):
+		return setInteger(out, decoded)
+	default:
+		out.Set(decoded.Convert(out.Type()))
+	}
+	return nil
+}
+
+// queryFields selects the columns of fields from the rows matching filter and passes each row's
+// raw values to fn, in the order of fields.
+func (r *RegisteredStruct[T]) queryFields(ctx context.Context, filter *Filter, fields []*RegisteredStructField, fn func(values []any) error) error {
+	if r.db == nil {
+		return ErrDatabaseNotInitialized
+	}
+
+	if len(fields) == 0 {
+		return fmt.Errorf("select fields of %s requires at least one field", r.Name)
+	}
+
+	columns := make([]string, len(fields))
+	for i, field := range fields {
+		if field == nil || !r.hasField(field) {
+			return fmt.Errorf("select fields requires fields of %s", r.Name)
+		}
+		columns[i] = r.quotedColumn(*field)
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s", strings.Join(columns, ", "), r.quotedName())
+
+	var args []any
+	if filter != nil {
+		filterString, filterArgs, err := filter.build(r.dialect())
+		if err != nil {
+			return fmt.Errorf("failed to build filter: %w", err)
+		}
+
+		if filterString = strings.TrimSpace(filterString); filterString != "" {
+			query += " " + filterString
+		}
+		args = filterArgs
+	}
+
+	q, release, err := r.readExecutor(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	rows, err := q.QueryContext(ctx, query+";", args...)
+	if err != nil {
+		return fmt.Errorf("failed to query fields from %s: %w", r.Name, err)
+	}
+	defer rows.Close()
+
+	values := make([]any, len(fields))
+	scanArgs := make([]any, len(fields))
+	for i := range values {
+		scanArgs[i] = &values[i]
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(scanArgs...); err != nil {
+			return fmt.Errorf("failed to scan fields from %s: %w", r.Name, err)
+		}
+
+		if err := fn(values); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("rows iteration error for %s: %w", r.Name, err)
+	}
+
+	return nil
+}
+
+// hasField reports whether field is one of r's fields rather than a field of another struct.
+func (r *RegisteredStruct[T]) hasField(field *RegisteredStructField) bool {
+	registered := r.FieldBySQLName(field.Opts.KeyName)
+	return registered != nil && slices.Equal(registered.Index, field.Index)
+}
@@ -0,0 +1,64 @@
+package gomysql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// selectManyChunkSize caps the keys per SelectMany query; larger IN lists plan poorly.
+const selectManyChunkSize = 500
+
+// SelectMany loads the rows with the given primary keys, taking keys in any form Select accepts.
+// Keys are queried in chunks with IN, so the returned rows are in no particular order and keys
+// without a row are skipped.
+func SelectMany[K, T any](r *RegisteredStruct[T], keys []K) ([]*T, error) {
+	return SelectManyCtx(context.Background(), r, keys)
+}
+
+func SelectManyCtx[K, T any](ctx context.Context, r *RegisteredStruct[T], keys []K) ([]*T, error) {
+	if r.db == nil {
+		return nil, ErrDatabaseNotInitialized
+	}
+
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	var (
+		width = len(r.PrimaryKeyFields)
+		size  = min(selectManyChunkSize, r.dialect().maxBindParams()/width)
+		args  = make([]any, 0, len(keys)*width)
+	)
+
+	for _, key := range keys {
+		keyArgs, err := r.keyArgs(key)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, keyArgs...)
+	}
+
+	// WHERE "id" IN (?, ?) or, for composite keys, WHERE ("a", "b") IN ((?, ?), (?, ?)).
+	columns, placeholder := r.quotedKeyColumns(), "?"
+	if width > 1 {
+		columns = "(" + columns + ")"
+		placeholder = "(" + strings.Repeat("?, ", width-1) + "?)"
+	}
+
+	var results []*T
+	for start := 0; start < len(keys); start += size {
+		count := min(size, len(keys)-start)
+		query := fmt.Sprintf("%s WHERE %s IN (%s);", r.selectAllSQL[:len(r.selectAllSQL)-1], columns,
+			strings.Repeat(placeholder+", ", count-1)+placeholder)
+
+		if err := r.streamRows(ctx, query, args[start*width:(start+count)*width], func(item *T) bool {
+			results = append(results, item)
+			return true
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	return results, nil
+}
@@ -1,11 +1,16 @@
 package gomysql
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 )
 
 func (r *RegisteredStruct[T]) Update(item *T) error {
+	return r.UpdateCtx(context.Background(), item)
+}
+
+func (r *RegisteredStruct[T]) UpdateCtx(ctx context.Context, item *T) error {
 	if r.db == nil {
 		return ErrDatabaseNotInitialized
 	}
@@ -23,7 +28,7 @@ func (r *RegisteredStruct[T]) Update(item *T) error {
 		}
 	}
 
-	if primaryKeyField := r.PrimaryKeyField; primaryKeyField.RealName != "" {
+	for _, primaryKeyField := range r.PrimaryKeyFields {
 		if val, err := getSQLValueOf(primaryKeyField, elem.FieldByIndex(primaryKeyField.Index)); err != nil {
 			return fmt.Errorf("value conversion %s: %w", primaryKeyField.Opts.KeyName, err)
 		} else {
@@ -31,10 +36,18 @@ func (r *RegisteredStruct[T]) Update(item *T) error {
 		}
 	}
 
-	r.db.lock.Lock()
-	defer r.db.lock.Unlock()
+	// A struct made only of key columns has nothing to update.
+	if r.updateSQL == "" {
+		return nil
+	}
+
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
 
-	if _, err := r.db.db.Exec(r.updateSQL, values...); err != nil {
+	if _, err := r.executor().ExecContext(ctx, r.updateSQL, values...); err != nil {
 		return fmt.Errorf("update fail %s: %w", r.Name, err)
 	}
 
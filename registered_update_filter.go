@@ -1,6 +1,7 @@
 package gomysql
 
 import (
+	"context"
 	"fmt"
 	"strings"
 )
@@ -16,7 +17,7 @@ func SetField(field *RegisteredStructField, value any) UpdateAssignment {
 	}
 
 	return UpdateAssignment{
-		clause: fmt.Sprintf("%s = ?", field.Opts.KeyName),
+		clause: fmt.Sprintf("%s = ?", markIdent(field.Opts.KeyName)),
 		args:   []any{arg},
 	}
 }
@@ -31,43 +32,47 @@ func SetExpr(field *RegisteredStructField, expr string, args ...any) UpdateAssig
 	}
 
 	return UpdateAssignment{
-		clause: fmt.Sprintf("%s = %s", field.Opts.KeyName, expr),
+		clause: fmt.Sprintf("%s = %s", markIdent(field.Opts.KeyName), expr),
 		args:   args,
 	}
 }
 
 func SetAdd(field *RegisteredStructField, value any) UpdateAssignment {
-	return SetExpr(field, fmt.Sprintf("%s + ?", field.Opts.KeyName), value)
+	return SetExpr(field, fmt.Sprintf("%s + ?", markIdent(field.Opts.KeyName)), value)
 }
 
 func SetSub(field *RegisteredStructField, value any) UpdateAssignment {
-	return SetExpr(field, fmt.Sprintf("%s - ?", field.Opts.KeyName), value)
+	return SetExpr(field, fmt.Sprintf("%s - ?", markIdent(field.Opts.KeyName)), value)
 }
 
 func SetMul(field *RegisteredStructField, value any) UpdateAssignment {
-	return SetExpr(field, fmt.Sprintf("%s * ?", field.Opts.KeyName), value)
+	return SetExpr(field, fmt.Sprintf("%s * ?", markIdent(field.Opts.KeyName)), value)
 }
 
 func SetDiv(field *RegisteredStructField, value any) UpdateAssignment {
-	return SetExpr(field, fmt.Sprintf("%s / ?", field.Opts.KeyName), value)
+	return SetExpr(field, fmt.Sprintf("%s / ?", markIdent(field.Opts.KeyName)), value)
 }
 
 func (r *RegisteredStruct[T]) UpdateWithFilter(filter *Filter, assignments ...UpdateAssignment) (int64, error) {
+	return r.UpdateWithFilterCtx(context.Background(), filter, assignments...)
+}
+
+func (r *RegisteredStruct[T]) UpdateWithFilterCtx(ctx context.Context, filter *Filter, assignments ...UpdateAssignment) (int64, error) {
 	if r.db == nil {
 		return 0, ErrDatabaseNotInitialized
 	}
 
-	setClause, setArgs, err := buildUpdateAssignments(assignments)
+	setClause, setArgs, err := buildUpdateAssignments(r.dialect(), assignments)
 	if err != nil {
 		return 0, err
 	}
 
-	filterClause, filterArgs, err := buildFilterClause(filter)
+	filterClause, filterArgs, err := buildFilterClause(r.dialect(), filter)
 	if err != nil {
 		return 0, err
 	}
 
-	sql := fmt.Sprintf("UPDATE %s SET %s", r.Name, setClause)
+	sql := fmt.Sprintf("UPDATE %s SET %s", r.quotedName(), setClause)
 	if filterClause != "" {
 		sql += " " + filterClause
 	}
@@ -75,10 +80,13 @@ func (r *RegisteredStruct[T]) UpdateWithFilter(filter *Filter, assignments ...Up
 
 	args := append(setArgs, filterArgs...)
 
-	r.db.lock.Lock()
-	defer r.db.lock.Unlock()
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer release()
 
-	result, err := r.db.db.Exec(sql, args...)
+	result, err := r.executor().ExecContext(ctx, sql, args...)
 	if err != nil {
 		return 0, fmt.Errorf("update with filter fail %s: %w", r.Name, err)
 	}
@@ -92,6 +100,10 @@ func (r *RegisteredStruct[T]) UpdateWithFilter(filter *Filter, assignments ...Up
 }
 
 func (r *RegisteredStruct[T]) UpdateWithFilterReturning(filter *Filter, returning []*RegisteredStructField, assignments ...UpdateAssignment) ([]ReturnedValues, error) {
+	return r.UpdateWithFilterReturningCtx(context.Background(), filter, returning, assignments...)
+}
+
+func (r *RegisteredStruct[T]) UpdateWithFilterReturningCtx(ctx context.Context, filter *Filter, returning []*RegisteredStructField, assignments ...UpdateAssignment) ([]ReturnedValues, error) {
 	if r.db == nil {
 		return nil, ErrDatabaseNotInitialized
 	}
@@ -100,12 +112,16 @@ func (r *RegisteredStruct[T]) UpdateWithFilterReturning(filter *Filter, returnin
 		return nil, fmt.Errorf("returning requires at least one field")
 	}
 
-	setClause, setArgs, err := buildUpdateAssignments(assignments)
+	if !r.dialect().supportsReturning() {
+		return nil, fmt.Errorf("update returning %s: %w", r.Name, ErrUnsupportedByDialect)
+	}
+
+	setClause, setArgs, err := buildUpdateAssignments(r.dialect(), assignments)
 	if err != nil {
 		return nil, err
 	}
 
-	filterClause, filterArgs, err := buildFilterClause(filter)
+	filterClause, filterArgs, err := buildFilterClause(r.dialect(), filter)
 	if err != nil {
 		return nil, err
 	}
@@ -115,10 +131,10 @@ func (r *RegisteredStruct[T]) UpdateWithFilterReturning(filter *Filter, returnin
 		if field == nil {
 			return nil, fmt.Errorf("returning requires valid fields")
 		}
-		returningCols = append(returningCols, field.Opts.KeyName)
+		returningCols = append(returningCols, r.quotedColumn(*field))
 	}
 
-	sql := fmt.Sprintf("UPDATE %s SET %s", r.Name, setClause)
+	sql := fmt.Sprintf("UPDATE %s SET %s", r.quotedName(), setClause)
 	if filterClause != "" {
 		sql += " " + filterClause
 	}
@@ -126,10 +142,13 @@ func (r *RegisteredStruct[T]) UpdateWithFilterReturning(filter *Filter, returnin
 
 	args := append(setArgs, filterArgs...)
 
-	r.db.lock.Lock()
-	defer r.db.lock.Unlock()
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
 
-	rows, err := r.db.db.Query(sql, args...)
+	rows, err := r.executor().QueryContext(ctx, sql, args...)
 	if err != nil {
 		return nil, fmt.Errorf("update returning fail %s: %w", r.Name, err)
 	}
@@ -165,7 +184,7 @@ func (r *RegisteredStruct[T]) UpdateWithFilterReturning(filter *Filter, returnin
 	return results, nil
 }
 
-func buildUpdateAssignments(assignments []UpdateAssignment) (string, []any, error) {
+func buildUpdateAssignments(d Dialect, assignments []UpdateAssignment) (string, []any, error) {
 	if len(assignments) == 0 {
 		return "", nil, fmt.Errorf("update requires at least one assignment")
 	}
@@ -182,15 +201,15 @@ func buildUpdateAssignments(assignments []UpdateAssignment) (string, []any, erro
 		}
 	}
 
-	return strings.Join(clauses, ", "), args, nil
+	return renderIdents(d, strings.Join(clauses, ", ")), args, nil
 }
 
-func buildFilterClause(filter *Filter) (string, []any, error) {
+func buildFilterClause(d Dialect, filter *Filter) (string, []any, error) {
 	if filter == nil {
 		return "", nil, nil
 	}
 
-	filterString, filterArgs, err := filter.Build()
+	filterString, filterArgs, err := filter.build(d)
 	if err != nil {
 		return "", nil, fmt.Errorf("failed to build filter: %w", err)
 	}
@@ -199,7 +218,7 @@ func buildFilterClause(filter *Filter) (string, []any, error) {
 	return filterString, filterArgs, nil
 }
 
-func buildWhereClause(filter *Filter) (string, []any, error) {
+func buildWhereClause(d Dialect, filter *Filter) (string, []any, error) {
 	if filter == nil {
 		return "", nil, nil
 	}
@@ -212,7 +231,7 @@ func buildWhereClause(filter *Filter) (string, []any, error) {
 		return "", nil, nil
 	}
 
-	return "WHERE " + strings.Join(filter.whereTokens, " "), filter.args, nil
+	return renderIdents(d, "WHERE "+strings.Join(filter.whereTokens, " ")), filter.args, nil
 }
 
 func filterHasWhere(filter *Filter) bool {
@@ -31,17 +31,27 @@ func normalizeValueForField(field RegisteredStructField, value any) (any, error)
 			Type:         baseType,
 			Index:        field.Index,
 			InternalType: field.InternalType,
+			codec:        field.codec,
 		}, rv)
 	}
 
-	if rv.Type().ConvertibleTo(baseType) {
-		converted := rv.Convert(baseType)
+	// Integers are passed through rather than converted to one-rune strings.
+	if decodableAs(rv.Type(), baseType) {
+		converted := reflect.New(baseType).Elem()
+		if (rv.CanInt() || rv.CanUint()) && (converted.CanInt() || converted.CanUint()) {
+			if err := setInteger(converted, rv); err != nil {
+				return nil, err
+			}
+		} else {
+			converted = rv.Convert(baseType)
+		}
 		return getSQLValueOf(RegisteredStructField{
 			Opts:         field.Opts,
 			RealName:     field.RealName,
 			Type:         baseType,
 			Index:        field.Index,
 			InternalType: field.InternalType,
+			codec:        field.codec,
 		}, converted)
 	}
 
@@ -61,41 +71,90 @@ func (f *Filter) KeyCmp(key *RegisteredStructField, op SQLOperator, value any) *
 		panic("KeyCmp requires a valid key")
 	}
 
+	return f.compare("KeyCmp", markIdent(key.Opts.KeyName), op, value, func(value any) (any, error) {
+		arg, err := normalizeValueForField(*key, value)
+		if err != nil {
+			return nil, fmt.Errorf("KeyCmp failed to normalize value for %s: %v", key.Opts.KeyName, err)
+		}
+		return arg, nil
+	})
+}
+
+// JSONCmp compares the value at path inside a JSON field's documents, such as
+// $.address.city or $.tags[0]. The value is bound as is. PostgreSQL extracts the value as
+// text and casts it to numeric for number values and to boolean for bool values.
+func (f *Filter) JSONCmp(key *RegisteredStructField, path string, op SQLOperator, value any) *Filter {
+	if key == nil || key.InternalType != TypeRepJSON {
+		panic("JSONCmp requires a JSON field")
+	}
+
+	if !strings.HasPrefix(path, "$") || strings.ContainsAny(path, identMark+jsonMark) {
+		panic(fmt.Sprintf("JSONCmp requires a path starting with $, got %q", path))
+	}
+
+	return f.compare("JSONCmp", markJSON(key.Opts.KeyName, path, jsonKindOf(value)), op, value, func(value any) (any, error) {
+		return value, nil
+	})
+}
+
+// AggregateCmp compares an aggregate, such as SumOf(field), in the Having filter of a
+// GroupBy query. Values compared with MinOf and MaxOf are converted like KeyCmp values; others
+// are bound as is.
+func (f *Filter) AggregateCmp(agg Aggregate, op SQLOperator, value any) *Filter {
+	if err := agg.check(func(*RegisteredStructField) bool { return true }); err != nil {
+		panic("AggregateCmp " + err.Error())
+	}
+
+	return f.compare("AggregateCmp", agg.expr(), op, value, func(value any) (any, error) {
+		if agg.function != "MIN" && agg.function != "MAX" {
+			return value, nil
+		}
+
+		arg, err := normalizeValueForField(*agg.field, value)
+		if err != nil {
+			return nil, fmt.Errorf("AggregateCmp failed to normalize value for %s: %v", agg.field.Opts.KeyName, err)
+		}
+		return arg, nil
+	})
+}
+
+// compare appends the condition "expr op value", binding value through normalize.
+func (f *Filter) compare(method, expr string, op SQLOperator, value any, normalize func(any) (any, error)) *Filter {
 	if !f.lastWasJoiner {
-		panic("KeyCmp must be preceded by a joiner (And/Or) or be the first condition")
+		panic(method + " must be preceded by a joiner (And/Or) or be the first condition")
 	}
 
 	switch op {
 	case OpIsNull, OpIsNotNull:
 		if value != nil {
-			panic("KeyCmp with IS NULL/IS NOT NULL does not accept a value")
+			panic(method + " with IS NULL/IS NOT NULL does not accept a value")
 		}
-		f.whereTokens = append(f.whereTokens, fmt.Sprintf("%s %s", key.Opts.KeyName, op))
+		f.whereTokens = append(f.whereTokens, fmt.Sprintf("%s %s", expr, op))
 	case OpIn, OpNotIn:
 		if value == nil {
-			panic("KeyCmp with IN/NOT IN requires a slice or array value")
+			panic(method + " with IN/NOT IN requires a slice or array value")
 		}
 		val := reflect.ValueOf(value)
 		if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
-			panic("KeyCmp with IN/NOT IN requires a slice or array value")
+			panic(method + " with IN/NOT IN requires a slice or array value")
 		}
 		if val.Len() == 0 {
-			panic("KeyCmp with IN/NOT IN requires at least one value")
+			panic(method + " with IN/NOT IN requires at least one value")
 		}
 		placeholders := strings.Repeat("?, ", val.Len()-1) + "?"
-		f.whereTokens = append(f.whereTokens, fmt.Sprintf("%s %s (%s)", key.Opts.KeyName, op, placeholders))
+		f.whereTokens = append(f.whereTokens, fmt.Sprintf("%s %s (%s)", expr, op, placeholders))
 		for i := 0; i < val.Len(); i++ {
-			arg, err := normalizeValueForField(*key, val.Index(i).Interface())
+			arg, err := normalize(val.Index(i).Interface())
 			if err != nil {
-				panic(fmt.Sprintf("KeyCmp failed to normalize value for %s: %v", key.Opts.KeyName, err))
+				panic(err.Error())
 			}
 			f.args = append(f.args, arg)
 		}
 	default:
-		f.whereTokens = append(f.whereTokens, fmt.Sprintf("%s %s ?", key.Opts.KeyName, op))
-		arg, err := normalizeValueForField(*key, value)
+		f.whereTokens = append(f.whereTokens, fmt.Sprintf("%s %s ?", expr, op))
+		arg, err := normalize(value)
 		if err != nil {
-			panic(fmt.Sprintf("KeyCmp failed to normalize value for %s: %v", key.Opts.KeyName, err))
+			panic(err.Error())
 		}
 		f.args = append(f.args, arg)
 	}
@@ -153,7 +212,8 @@ func (f *Filter) Ordering(field *RegisteredStructField, asc bool) *Filter {
 		dir = "DESC"
 	}
 
-	f.orderByClause = fmt.Sprintf("ORDER BY %s %s", field.RealName, dir)
+	f.orderByClause = fmt.Sprintf("ORDER BY %s %s", markIdent(field.Opts.KeyName), dir)
+	f.order = &PageOrder{Field: field, Desc: !asc}
 	f.lastWasJoiner = false
 	return f
 }
@@ -178,7 +238,30 @@ func (f *Filter) Offset(n int) *Filter {
 	return f
 }
 
+// Build renders the filter for SQLite, with identifiers quoted in the ANSI style and "?"
+// placeholders, and converts the arguments as BuildFor(SQLite) does, so times are bound in the
+// stored layout. The fragment also runs on MySQL only without quoted identifiers; use BuildFor
+// for other dialects. Registered structs render their filters with their driver's dialect.
 func (f *Filter) Build() (sqlFragment string, args []any, err error) {
+	return f.BuildFor(SQLite)
+}
+
+// BuildFor renders the filter for d: identifiers are quoted, placeholders written and
+// arguments converted the way d's driver expects, so PostgreSQL fragments number their
+// placeholders from $1.
+func (f *Filter) BuildFor(d Dialect) (sqlFragment string, args []any, err error) {
+	if d == nil {
+		return "", nil, fmt.Errorf("BuildFor requires a dialect")
+	}
+
+	if sqlFragment, args, err = f.build(d); err != nil {
+		return "", nil, err
+	}
+
+	return d.rebind(sqlFragment), dialectExecutor{dialect: d}.bindArgs(args), nil
+}
+
+func (f *Filter) build(d Dialect) (sqlFragment string, args []any, err error) {
 	if f.lastWasJoiner && len(f.whereTokens) > 0 {
 		return "", nil, fmt.Errorf("filter ends with a joiner; expected a condition")
 	}
@@ -200,5 +283,66 @@ func (f *Filter) Build() (sqlFragment string, args []any, err error) {
 		parts = append(parts, f.offsetClause)
 	}
 
-	return strings.Join(parts, " "), f.args, nil
+	return renderIdents(d, strings.Join(parts, " ")), f.args, nil
+}
+
+// identMark brackets the column names in filter tokens and update assignments, which are
+// built before the dialect that quotes them is known. Tag validation keeps it out of names.
+// Within a marked name, jsonMark separates a JSON column from a path inside it.
+const (
+	identMark = "\x00"
+	jsonMark  = "\x01"
+)
+
+func markIdent(name string) string {
+	return identMark + name + identMark
+}
+
+func markJSON(name, path string, kind jsonKind) string {
+	return identMark + name + jsonMark + path + jsonMark + string(kind) + identMark
+}
+
+// jsonKind is the kind of value a JSON path is compared with, for dialects that extract JSON
+// values as text and must cast them to compare numbers and booleans.
+type jsonKind byte
+
+const (
+	jsonText   jsonKind = 't'
+	jsonNumber jsonKind = 'n'
+	jsonBool   jsonKind = 'b'
+)
+
+// jsonKindOf classifies value, or the first element of an IN list.
+func jsonKindOf(value any) jsonKind {
+	rv := reflect.ValueOf(value)
+	if (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array) && rv.Len() > 0 {
+		rv = rv.Index(0)
+	}
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		rv = rv.Elem()
+	}
+
+	switch {
+	case rv.CanInt() || rv.CanUint() || rv.CanFloat():
+		return jsonNumber
+	case rv.Kind() == reflect.Bool:
+		return jsonBool
+	default:
+		return jsonText
+	}
+}
+
+// renderIdents replaces the marked column names in s with d's quoted identifiers, and marked
+// JSON paths with d's extraction expressions.
+func renderIdents(d Dialect, s string) string {
+	parts := strings.Split(s, identMark)
+	for i := 1; i < len(parts); i += 2 {
+		if name, rest, ok := strings.Cut(parts[i], jsonMark); ok {
+			path, kind, _ := strings.Cut(rest, jsonMark)
+			parts[i] = d.jsonExtract(d.quoteIdent(name), path, jsonKind(kind[0]))
+		} else {
+			parts[i] = d.quoteIdent(parts[i])
+		}
+	}
+	return strings.Join(parts, "")
 }
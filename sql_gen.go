@@ -2,17 +2,19 @@ package gomysql
 
 import (
 	"fmt"
+	"reflect"
+	"slices"
 	"strings"
 )
 
-func columnDefinition(field RegisteredStructField, includePrimaryKey bool) string {
+func columnDefinition(d Dialect, field RegisteredStructField, includePrimaryKey bool) string {
 	var parts []string
-	parts = append(parts, field.Opts.KeyName, typeNameString(field.InternalType))
+	parts = append(parts, d.quoteIdent(field.Opts.KeyName), d.columnType(field))
 
 	if includePrimaryKey && field.Opts.PrimaryKey {
 		parts = append(parts, "PRIMARY KEY")
-		if field.Opts.AutoIncr {
-			parts = append(parts, "AUTOINCREMENT")
+		if keyword := d.autoIncrementKeyword(); field.Opts.AutoIncr && keyword != "" {
+			parts = append(parts, keyword)
 		}
 	}
 
@@ -24,63 +26,286 @@ func columnDefinition(field RegisteredStructField, includePrimaryKey bool) strin
 		parts = append(parts, "NOT NULL")
 	}
 
-	if field.Opts.HasForeignKey() {
-		parts = append(parts, fmt.Sprintf("REFERENCES %s(%s)", field.Opts.ForeignKey.TableName, field.Opts.ForeignKey.ColumnName))
+	if field.Opts.Default != "" {
+		parts = append(parts, "DEFAULT "+field.Opts.Default)
+	}
+
+	if field.Opts.Check != "" {
+		parts = append(parts, "CHECK ("+field.Opts.Check+")")
+	}
+
+	if field.Opts.HasForeignKey() && d.inlineForeignKeys() {
+		parts = append(parts, foreignKeyReference(d, field.Opts.ForeignKey))
 	}
 
 	return strings.Join(parts, " ")
 }
 
+func foreignKeyReference(d Dialect, ref *ForeignKeyRef) string {
+	table := quoteTable(d, ref.TableName)
+	if !d.crossSchemaForeignKeys() {
+		_, base := splitTableName(ref.TableName)
+		table = d.quoteIdent(base)
+	}
+
+	reference := fmt.Sprintf("REFERENCES %s(%s)", table, d.quoteIdent(ref.ColumnName))
+	if ref.OnDelete != "" {
+		reference += " ON DELETE " + ref.OnDelete
+	}
+	if ref.OnUpdate != "" {
+		reference += " ON UPDATE " + ref.OnUpdate
+	}
+	return reference
+}
+
+func foreignKeyConstraint(d Dialect, field RegisteredStructField) string {
+	return fmt.Sprintf("FOREIGN KEY (%s) %s", d.quoteIdent(field.Opts.KeyName), foreignKeyReference(d, field.Opts.ForeignKey))
+}
+
+func joinColumns(columns []string) string {
+	return strings.Join(columns, ", ")
+}
+
+func placeholders(n int) string {
+	return strings.Repeat("?, ", n-1) + "?"
+}
+
+// onConflictUpsertSQL renders INSERT ... ON CONFLICT (...) DO UPDATE, shared by SQLite and
+// PostgreSQL. With nothing to update the conflicting row is left as is.
+func onConflictUpsertSQL(table string, columns, conflictColumns, updateColumns []string) string {
+	var updates []string
+	for _, column := range updateColumns {
+		updates = append(updates, fmt.Sprintf("%s = excluded.%s", column, column))
+	}
+
+	conflict := "DO NOTHING"
+	if len(updates) > 0 {
+		conflict = "DO UPDATE SET " + strings.Join(updates, ", ")
+	}
+
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (%s) %s;", table, joinColumns(columns), placeholders(len(columns)), joinColumns(conflictColumns), conflict)
+}
+
+// pinStatements keeps the static statements prepared in the driver's statement caches.
+func (r *RegisteredStruct[T]) pinStatements() {
+	for _, cache := range []*stmtCache{r.db.stmts, r.db.readStmts} {
+		if cache != nil {
+			cache.pin(r.Name, r.insertSQL, r.insertOrIgnoreSQL, r.selectSQL, r.updateSQL, r.deleteSQL, r.listSQL, r.selectAllSQL)
+		}
+	}
+}
+
+// invalidateStatements closes r's prepared statements after its table changed.
+func (r *RegisteredStruct[T]) invalidateStatements() {
+	for _, cache := range []*stmtCache{r.db.stmts, r.db.readStmts} {
+		if cache != nil {
+			cache.invalidate(r.Name)
+		}
+	}
+}
+
+func (r *RegisteredStruct[T]) dialect() Dialect {
+	return r.db.dialect
+}
+
+func (r *RegisteredStruct[T]) quotedName() string {
+	return quoteTable(r.dialect(), r.Name)
+}
+
+// splitTableName separates the schema (or attached database) prefix of a table name such as
+// archive.users from the table itself.
+func splitTableName(name string) (schema, table string) {
+	if schema, table, ok := strings.Cut(name, "."); ok {
+		return schema, table
+	}
+	return "", name
+}
+
+// quoteTable quotes a possibly schema-qualified table name part by part.
+func quoteTable(d Dialect, name string) string {
+	if schema, table := splitTableName(name); schema != "" {
+		return d.quoteIdent(schema) + "." + d.quoteIdent(table)
+	}
+	return d.quoteIdent(name)
+}
+
+// schemaPredicate returns the SQL for the schema a table lives in: a placeholder bound to its
+// prefix, or current when the name is unqualified.
+func schemaPredicate(schema, current string) (string, []any) {
+	if schema == "" {
+		return current, nil
+	}
+	return "?", []any{schema}
+}
+
+func (r *RegisteredStruct[T]) quotedColumn(field RegisteredStructField) string {
+	return r.dialect().quoteIdent(field.Opts.KeyName)
+}
+
 // CREATE TABLE IF NOT EXISTS X (key1 INTEGER PRIMARY KEY, key2 TEXT, ...);
-// with autoincrement: INSERT OR REPLACE INTO X (key2, ...) VALUES (?, ...);
-// without autoincrement: INSERT OR REPLACE INTO X (key1, key2, ...) VALUES (?, ?, ...);
-// SELECT key2, ... FROM X WHERE key1 = ?;
+// with a composite key: CREATE TABLE IF NOT EXISTS X (key1 INTEGER, key2 TEXT, ..., PRIMARY KEY (key1, key2));
+// with autoincrement: INSERT INTO X (key2, ...) VALUES (?, ...);
+// without autoincrement: INSERT INTO X (key1, key2, ...) VALUES (?, ?, ...);
+// SELECT key1, key2, ... FROM X WHERE key1 = ?;
 // UPDATE X SET key2 = ?, ... WHERE key1 = ?;
 // DELETE FROM X WHERE key1 = ?;
 // SELECT key1 FROM X;
+// Composite keys match on every key column: WHERE key1 = ? AND key2 = ?.
+// Identifiers, column types and the insert-or-ignore statement are rendered by the driver's
+// dialect.
 func generateSQLStatements[T any](r *RegisteredStruct[T]) {
-	var pKey RegisteredStructField
+	var (
+		d         = r.dialect()
+		keys      []RegisteredStructField
+		composite bool
+	)
+
 	for _, field := range r.Fields {
 		if field.Opts.PrimaryKey {
-			pKey = field
-			break
+			keys = append(keys, field)
 		}
 	}
+	composite = len(keys) > 1
 
-	r.createTableSQL = fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (", r.Name)
-	var pKeyStr = columnDefinition(pKey, true)
+	var table = r.quotedName()
+	r.createTableSQL = fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (", table)
 
-	if !pKey.Opts.AutoIncr {
-		r.insertOrdered = append(r.insertOrdered, pKey)
-	}
+	for _, key := range keys {
+		if !key.Opts.AutoIncr {
+			r.insertOrdered = append(r.insertOrdered, key)
+		}
 
-	r.createTableSQL += pKeyStr + ", "
+		r.createTableSQL += columnDefinition(d, key, !composite) + ", "
+	}
 
 	for _, field := range r.Fields {
 		if field.Opts.PrimaryKey {
 			continue
 		}
 
-		var str = columnDefinition(field, false)
+		var str = columnDefinition(d, field, false)
 		r.createTableSQL += str + ", "
 		r.insertOrdered = append(r.insertOrdered, field)
 		r.nonInsertionOrdered = append(r.nonInsertionOrdered, field)
 	}
 
-	var mapper = func(fields []RegisteredStructField, joiner string) string {
+	var columns = func(fields []RegisteredStructField) []string {
 		var parts []string
 		for _, field := range fields {
-			parts = append(parts, field.Opts.KeyName)
+			parts = append(parts, d.quoteIdent(field.Opts.KeyName))
+		}
+		return parts
+	}
+
+	var mapper = func(fields []RegisteredStructField, joiner string) string {
+		return strings.Join(columns(fields), joiner)
+	}
+
+	if composite {
+		r.createTableSQL += fmt.Sprintf("PRIMARY KEY (%s), ", mapper(keys, ", "))
+	}
+
+	if !d.inlineForeignKeys() {
+		for _, field := range r.Fields {
+			if field.Opts.HasForeignKey() {
+				r.createTableSQL += foreignKeyConstraint(d, field) + ", "
+			}
 		}
-		return strings.Join(parts, joiner)
 	}
 
+	var (
+		keyColumns = mapper(keys, ", ")
+		keyWhere   = mapper(keys, " = ? AND ") + " = ?"
+		allColumns = mapper(append(slices.Clone(keys), r.nonInsertionOrdered...), ", ")
+	)
+
 	r.createTableSQL = strings.TrimSuffix(r.createTableSQL, ", ") + ");"
-	r.insertSQL = fmt.Sprintf("INSERT OR REPLACE INTO %s (%s) VALUES (%s);", r.Name, mapper(r.insertOrdered, ", "), strings.Repeat("?, ", len(r.insertOrdered)-1)+"?")
-	r.updateSQL = fmt.Sprintf("UPDATE %s SET %s WHERE %s = ?;", r.Name, mapper(r.nonInsertionOrdered, " = ?, ")+" = ?", pKey.Opts.KeyName)
-	r.selectSQL = fmt.Sprintf("SELECT %s FROM %s WHERE %s = ?;", mapper(r.nonInsertionOrdered, ", "), r.Name, pKey.Opts.KeyName)
-	r.deleteSQL = fmt.Sprintf("DELETE FROM %s WHERE %s = ?;", r.Name, pKey.Opts.KeyName)
-	r.listSQL = fmt.Sprintf("SELECT %s FROM %s;", pKey.Opts.KeyName, r.Name)
-	r.selectAllSQL = fmt.Sprintf("SELECT %s, %s FROM %s;", pKey.Opts.KeyName, mapper(r.nonInsertionOrdered, ", "), r.Name)
-	r.PrimaryKeyField = pKey
+	r.insertSQL = fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s);", table, mapper(r.insertOrdered, ", "), placeholders(len(r.insertOrdered)))
+	r.insertOrIgnoreSQL = d.insertOrIgnoreSQL(table, columns(r.insertOrdered))
+	if len(r.nonInsertionOrdered) > 0 {
+		r.updateSQL = fmt.Sprintf("UPDATE %s SET %s WHERE %s;", table, mapper(r.nonInsertionOrdered, " = ?, ")+" = ?", keyWhere)
+	}
+	r.selectSQL = fmt.Sprintf("SELECT %s FROM %s WHERE %s;", allColumns, table, keyWhere)
+	r.deleteSQL = fmt.Sprintf("DELETE FROM %s WHERE %s;", table, keyWhere)
+	r.listSQL = fmt.Sprintf("SELECT %s FROM %s;", keyColumns, table)
+	r.selectAllSQL = fmt.Sprintf("SELECT %s FROM %s;", allColumns, table)
+	r.PrimaryKeyField = keys[0]
+	r.PrimaryKeyFields = keys
+}
+
+// quotedKeyColumns lists the primary key columns, comma separated.
+func (r *RegisteredStruct[T]) quotedKeyColumns() string {
+	var parts []string
+	for _, key := range r.PrimaryKeyFields {
+		parts = append(parts, r.quotedColumn(key))
+	}
+	return joinColumns(parts)
+}
+
+// keyArgs turns the key passed to Select or Delete into statement arguments, one per primary
+// key column. Composite keys are given as a tuple ([]any in key order), a T or *T with the key
+// fields set, or any struct with fields named like the key fields. Each value is converted
+// like a filter value, so codec, Valuer and TextMarshaler keys work.
+func (r *RegisteredStruct[T]) keyArgs(key any) ([]any, error) {
+	if len(r.PrimaryKeyFields) == 1 {
+		arg, err := normalizeValueForField(r.PrimaryKeyFields[0], key)
+		if err != nil {
+			return nil, fmt.Errorf("value conversion %s: %w", r.PrimaryKeyFields[0].Opts.KeyName, err)
+		}
+		return []any{arg}, nil
+	}
+
+	value := reflect.ValueOf(key)
+	if value.Kind() == reflect.Pointer && !value.IsNil() {
+		value = value.Elem()
+	}
+
+	var args []any
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		if value.Len() != len(r.PrimaryKeyFields) {
+			return nil, fmt.Errorf("key for %s has %d values, expected %d", r.Name, value.Len(), len(r.PrimaryKeyFields))
+		}
+
+		for i, field := range r.PrimaryKeyFields {
+			arg, err := normalizeValueForField(field, value.Index(i).Interface())
+			if err != nil {
+				return nil, fmt.Errorf("value conversion %s: %w", field.Opts.KeyName, err)
+			}
+			args = append(args, arg)
+		}
+	case reflect.Struct:
+		for _, field := range r.PrimaryKeyFields {
+			var fieldValue reflect.Value
+			if value.Type() == r.Type {
+				fieldValue = value.FieldByIndex(field.Index)
+			} else if fieldValue = fieldByGoPath(value, field.RealName); !fieldValue.IsValid() {
+				return nil, fmt.Errorf("key struct %s has no field %s", value.Type(), field.RealName)
+			}
+
+			arg, err := normalizeValueForField(field, fieldValue.Interface())
+			if err != nil {
+				return nil, fmt.Errorf("value conversion %s: %w", field.Opts.KeyName, err)
+			}
+			args = append(args, arg)
+		}
+	default:
+		return nil, fmt.Errorf("key for %s must be a tuple or struct, got %T", r.Name, key)
+	}
+
+	return args, nil
+}
+
+// fieldByGoPath follows a dotted Go field path such as Audit.CreatedAt through v.
+func fieldByGoPath(v reflect.Value, path string) reflect.Value {
+	for _, name := range strings.Split(path, ".") {
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}
+		}
+
+		if v = v.FieldByName(name); !v.IsValid() {
+			break
+		}
+	}
+	return v
 }
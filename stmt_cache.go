@@ -0,0 +1,207 @@
+package gomysql
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+)
+
+const defaultStatementCacheSize = 256
+
+// stmtCache keeps prepared statements for one database handle. Statements registered with
+// pin, the static SQL of each registered struct, stay prepared until their owner is
+// invalidated; other statements, such as rendered filters, are kept in an LRU of limited
+// size. Statements are reference counted so eviction never closes one that is in use.
+type stmtCache struct {
+	mu       sync.Mutex
+	db       *sql.DB
+	dialect  Dialect
+	capacity int
+	pinned   map[string]string // query -> owner
+	entries  map[string]*cachedStmt
+	lru      *list.List // dynamic entries, most recently used first
+	closed   bool
+}
+
+type cachedStmt struct {
+	stmt    *sql.Stmt
+	query   string
+	owner   string
+	users   int
+	evicted bool
+	elem    *list.Element
+}
+
+func newStmtCache(db *sql.DB, dialect Dialect, capacity int) *stmtCache {
+	return &stmtCache{
+		db:       db,
+		dialect:  dialect,
+		capacity: capacity,
+		pinned:   make(map[string]string),
+		entries:  make(map[string]*cachedStmt),
+		lru:      list.New(),
+	}
+}
+
+// pin marks queries as static statements of owner. They are prepared on first use and never
+// evicted by dynamic statements.
+func (c *stmtCache) pin(owner string, queries ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, query := range queries {
+		if query != "" {
+			c.pinned[query] = owner
+		}
+	}
+}
+
+// acquire returns the prepared statement for query, preparing it if needed. The caller must
+// pass the result to release once the statement has been executed.
+func (c *stmtCache) acquire(ctx context.Context, owner, query string) (*cachedStmt, error) {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil, errors.New("statement cache closed")
+	}
+
+	if entry, ok := c.entries[query]; ok {
+		entry.users++
+		if entry.elem != nil {
+			c.lru.MoveToFront(entry.elem)
+		}
+		c.mu.Unlock()
+		return entry, nil
+	}
+	c.mu.Unlock()
+
+	// Prepare outside the lock; a concurrent caller may prepare the same query, in which case
+	// the first one stored wins and the other statement is closed.
+	stmt, err := c.db.PrepareContext(ctx, c.dialect.rebind(query))
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		stmt.Close()
+		return nil, errors.New("statement cache closed")
+	}
+
+	if entry, ok := c.entries[query]; ok {
+		stmt.Close()
+		entry.users++
+		return entry, nil
+	}
+
+	entry := &cachedStmt{stmt: stmt, query: query, owner: owner, users: 1}
+	if pinnedOwner, ok := c.pinned[query]; ok {
+		entry.owner = pinnedOwner
+	} else {
+		entry.elem = c.lru.PushFront(entry)
+		for c.lru.Len() > c.capacity {
+			c.evict(c.lru.Back().Value.(*cachedStmt))
+		}
+	}
+
+	c.entries[query] = entry
+	return entry, nil
+}
+
+func (c *stmtCache) release(entry *cachedStmt) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry.users--
+	if entry.evicted && entry.users == 0 {
+		entry.stmt.Close()
+	}
+}
+
+// evict removes entry from the cache and closes it once no caller is using it. c.mu must be held.
+func (c *stmtCache) evict(entry *cachedStmt) {
+	if entry.evicted {
+		return
+	}
+
+	entry.evicted = true
+	delete(c.entries, entry.query)
+	if entry.elem != nil {
+		c.lru.Remove(entry.elem)
+		entry.elem = nil
+	}
+
+	if entry.users == 0 {
+		entry.stmt.Close()
+	}
+}
+
+// invalidate closes every statement prepared for owner, static and dynamic. Static queries
+// stay pinned and are prepared again on next use.
+func (c *stmtCache) invalidate(owner string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, entry := range c.entries {
+		if entry.owner == owner {
+			c.evict(entry)
+		}
+	}
+}
+
+func (c *stmtCache) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closed = true
+	for _, entry := range c.entries {
+		c.evict(entry)
+	}
+}
+
+// stmtExecutor runs statements through a statement cache. If a statement cannot be prepared
+// it is executed directly, so errors surface from the query itself.
+type stmtExecutor struct {
+	cache *stmtCache
+	owner string
+}
+
+func (e stmtExecutor) direct() dialectExecutor {
+	return dialectExecutor{q: e.cache.db, dialect: e.cache.dialect}
+}
+
+func (e stmtExecutor) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	entry, err := e.cache.acquire(ctx, e.owner, query)
+	if err != nil {
+		return e.direct().ExecContext(ctx, query, args...)
+	}
+	defer e.cache.release(entry)
+
+	return entry.stmt.ExecContext(ctx, e.direct().bindArgs(args)...)
+}
+
+// Rows and Row returned by a statement keep it open until they are closed, so releasing the
+// entry right after the call is safe.
+func (e stmtExecutor) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	entry, err := e.cache.acquire(ctx, e.owner, query)
+	if err != nil {
+		return e.direct().QueryContext(ctx, query, args...)
+	}
+	defer e.cache.release(entry)
+
+	return entry.stmt.QueryContext(ctx, e.direct().bindArgs(args)...)
+}
+
+func (e stmtExecutor) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	entry, err := e.cache.acquire(ctx, e.owner, query)
+	if err != nil {
+		return e.direct().QueryRowContext(ctx, query, args...)
+	}
+	defer e.cache.release(entry)
+
+	return entry.stmt.QueryRowContext(ctx, e.direct().bindArgs(args)...)
+}
@@ -0,0 +1,124 @@
+package gomysql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type CachedItem struct {
+	ID   int    `gomysql:"id,primary,increment"`
+	Name string `gomysql:"name"`
+}
+
+func TestStatementCacheReusesStaticStatements(t *testing.T) {
+	driver, err := Open(":memory:", DriverOptions{StatementCacheSize: 2})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer driver.Close()
+
+	items, err := RegisterOn(driver, CachedItem{})
+	if err != nil {
+		t.Fatalf("failed to register item: %v", err)
+	}
+
+	item := &CachedItem{Name: "first"}
+	if err := items.Insert(item); err != nil {
+		t.Fatalf("failed to insert item: %v", err)
+	}
+
+	if _, err := items.Select(item.ID); err != nil {
+		t.Fatalf("failed to select item: %v", err)
+	}
+
+	cache := driver.stmts
+	insert := cache.entries[items.insertSQL]
+	if insert == nil {
+		t.Fatalf("expected the insert statement to be cached")
+	}
+	assert.Nil(t, insert.elem, "expected static statements to be pinned")
+
+	for i := range 5 {
+		filter := NewFilter().KeyCmp(items.FieldByGoName("ID"), OpGreaterThan, i)
+		for range i + 1 {
+			filter.And().KeyCmp(items.FieldByGoName("ID"), OpGreaterThan, i)
+		}
+
+		if _, err := items.SelectAllWithFilter(filter); err != nil {
+			t.Fatalf("failed to select with filter %d: %v", i, err)
+		}
+	}
+
+	assert.Equal(t, 2, cache.lru.Len(), "expected dynamic statements to be bounded")
+	assert.Same(t, insert, cache.entries[items.insertSQL], "expected pinned statements to survive eviction")
+
+	if err := items.Insert(&CachedItem{Name: "second"}); err != nil {
+		t.Fatalf("failed to insert item: %v", err)
+	}
+	assert.Same(t, insert, cache.entries[items.insertSQL], "expected the insert statement to be reused")
+
+	if _, err := items.Migrate(MigrationOptions{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	assert.Empty(t, cache.entries, "expected Migrate to invalidate the struct's statements")
+	assert.Equal(t, 0, cache.lru.Len())
+
+	if _, err := items.Select(item.ID); err != nil {
+		t.Fatalf("failed to select item after migrate: %v", err)
+	}
+	assert.NotNil(t, cache.entries[items.selectSQL], "expected the statement to be prepared again")
+}
+
+func TestStatementCacheKeepsStatementsInUse(t *testing.T) {
+	driver, err := Open(":memory:", DriverOptions{StatementCacheSize: 1})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer driver.Close()
+
+	var (
+		ctx   = context.Background()
+		cache = driver.stmts
+	)
+
+	held, err := cache.acquire(ctx, "test", "SELECT 1;")
+	if err != nil {
+		t.Fatalf("failed to prepare statement: %v", err)
+	}
+
+	other, err := cache.acquire(ctx, "test", "SELECT 2;")
+	if err != nil {
+		t.Fatalf("failed to prepare statement: %v", err)
+	}
+	cache.release(other)
+
+	assert.True(t, held.evicted, "expected the older statement to be evicted")
+
+	var value int
+	if err := held.stmt.QueryRowContext(ctx).Scan(&value); err != nil {
+		t.Fatalf("expected an evicted statement in use to stay open: %v", err)
+	}
+	assert.Equal(t, 1, value)
+
+	cache.release(held)
+	assert.Error(t, held.stmt.QueryRowContext(ctx).Scan(&value), "expected the statement to be closed once released")
+}
+
+func TestStatementCacheDisabled(t *testing.T) {
+	driver, err := Open(":memory:", DriverOptions{StatementCacheSize: -1})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer driver.Close()
+
+	items, err := RegisterOn(driver, CachedItem{})
+	if err != nil {
+		t.Fatalf("failed to register item: %v", err)
+	}
+
+	assert.Nil(t, driver.stmts)
+	assert.IsType(t, dialectExecutor{}, items.executor(), "expected an uncached executor")
+	assert.NoError(t, items.Insert(&CachedItem{Name: "uncached"}))
+}
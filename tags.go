@@ -2,12 +2,26 @@ package gomysql
 
 import (
 	"fmt"
+	"slices"
 	"strings"
+	"unicode"
 )
 
 type ForeignKeyRef struct {
 	TableName  string
 	ColumnName string
+	OnDelete   string // referential action such as CASCADE, empty for the database default
+	OnUpdate   string
+}
+
+// foreignKeyActions are the referential actions accepted by ondelete: and onupdate:.
+var foreignKeyActions = []string{"CASCADE", "SET NULL", "RESTRICT", "NO ACTION"}
+
+// parseForeignKeyAction canonicalizes an ondelete:/onupdate: value, accepting any case and
+// underscores in place of spaces.
+func parseForeignKeyAction(value string) (string, bool) {
+	action := strings.ToUpper(strings.Join(strings.Fields(strings.ReplaceAll(value, "_", " ")), " "))
+	return action, slices.Contains(foreignKeyActions, action)
 }
 
 type SQLTagOpts struct {
@@ -16,11 +30,81 @@ type SQLTagOpts struct {
 	AutoIncr   bool
 	Unique     bool
 	NotNull    bool
+	Default    string // SQL literal or expression for DEFAULT, empty for none
+	Check      string // SQL expression for a column CHECK constraint, empty for none
+	Indexed    bool   // part of a secondary index
+	IndexName  string // index name shared by the fields of a multi-column index, empty for the default
 	ForeignKey *ForeignKeyRef
+	JSON       bool   // store a slice, map or struct as JSON text instead of a gob blob
+	Gob        bool   // store a slice, map or struct as a gob blob despite DriverOptions.JSON
+	Inline     bool   // a struct field whose tagged fields become columns of the table
+	Prefix     string // prepended to the column names of an inline struct
+}
+
+// splitTagOptions splits a tag on commas that are outside quotes and parentheses, so default:
+// and check: values can contain them.
+func splitTagOptions(tag string) []string {
+	var (
+		parts []string
+		depth int
+		quote rune
+		start int
+	)
+
+	for i, c := range tag {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == ',' && depth == 0:
+			parts = append(parts, tag[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(parts, tag[start:])
+}
+
+// validIdentifier reports whether name can be used as a column, table or index name: a letter
+// or underscore followed by letters, digits, underscores or dollar signs. Reserved words are
+// allowed since generated SQL quotes every identifier.
+func validIdentifier(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for i, c := range name {
+		switch {
+		case c == '_' || unicode.IsLetter(c):
+		case i > 0 && (c == '$' || unicode.IsDigit(c)):
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// validTableName reports whether name is an identifier, optionally prefixed by a schema or
+// attached database as in archive.users.
+func validTableName(name string) bool {
+	if schema, table, ok := strings.Cut(name, "."); ok {
+		return validIdentifier(schema) && validIdentifier(table)
+	}
+	return validIdentifier(name)
 }
 
 func mustParseTag(tag string) (output SQLTagOpts) {
-	parts := strings.Split(tag, ",")
+	var onDelete, onUpdate string
+
+	parts := splitTagOptions(tag)
 	if len(parts) == 0 {
 		panic(fmt.Sprintf("invalid tag format: %s", tag))
 	}
@@ -40,17 +124,69 @@ func mustParseTag(tag string) (output SQLTagOpts) {
 				output.Unique = true
 			case "notnull":
 				output.NotNull = true
+			case "index":
+				output.Indexed = true
+			case "json":
+				output.JSON = true
+			case "gob":
+				output.Gob = true
+			case "inline":
+				output.Inline = true
 			default:
+				if value, ok := strings.CutPrefix(part, "prefix:"); ok {
+					if output.Prefix = strings.TrimSpace(value); !validIdentifier(output.Prefix) {
+						panic(fmt.Sprintf("invalid prefix option: %s", part))
+					}
+					continue
+				}
+
+				if value, ok := strings.CutPrefix(part, "index:"); ok {
+					if output.IndexName = strings.TrimSpace(value); !validIdentifier(output.IndexName) {
+						panic(fmt.Sprintf("invalid index option: %s", part))
+					}
+					output.Indexed = true
+					continue
+				}
+
+				if value, ok := strings.CutPrefix(part, "default:"); ok {
+					if output.Default = strings.TrimSpace(value); output.Default == "" {
+						panic(fmt.Sprintf("invalid default option: %s", part))
+					}
+					continue
+				}
+
+				if value, ok := strings.CutPrefix(part, "check:"); ok {
+					if output.Check = strings.TrimSpace(value); output.Check == "" {
+						panic(fmt.Sprintf("invalid check option: %s", part))
+					}
+					continue
+				}
+
+				if value, ok := strings.CutPrefix(part, "ondelete:"); ok {
+					if onDelete, ok = parseForeignKeyAction(value); !ok {
+						panic(fmt.Sprintf("invalid ondelete option: %s", part))
+					}
+					continue
+				}
+
+				if value, ok := strings.CutPrefix(part, "onupdate:"); ok {
+					if onUpdate, ok = parseForeignKeyAction(value); !ok {
+						panic(fmt.Sprintf("invalid onupdate option: %s", part))
+					}
+					continue
+				}
+
 				if strings.HasPrefix(part, "fkey:") {
+					// The column follows the last dot, so the target may be a prefixed table.
 					ref := strings.TrimPrefix(part, "fkey:")
-					target := strings.Split(ref, ".")
-					if len(target) != 2 || strings.TrimSpace(target[0]) == "" || strings.TrimSpace(target[1]) == "" {
+					dot := strings.LastIndex(ref, ".")
+					if dot < 0 || !validTableName(strings.TrimSpace(ref[:dot])) || !validIdentifier(strings.TrimSpace(ref[dot+1:])) {
 						panic(fmt.Sprintf("invalid foreign key option: %s", part))
 					}
 
 					output.ForeignKey = &ForeignKeyRef{
-						TableName:  strings.TrimSpace(target[0]),
-						ColumnName: strings.TrimSpace(target[1]),
+						TableName:  strings.TrimSpace(ref[:dot]),
+						ColumnName: strings.TrimSpace(ref[dot+1:]),
 					}
 					continue
 				}
@@ -60,9 +196,33 @@ func mustParseTag(tag string) (output SQLTagOpts) {
 		}
 	}
 
+	if output.Inline || output.Prefix != "" {
+		// An inline struct has no column of its own, so it takes no name or column options.
+		if output != (SQLTagOpts{Inline: true, Prefix: output.Prefix}) || onDelete != "" || onUpdate != "" {
+			panic(fmt.Sprintf("invalid inline tag: %s", tag))
+		}
+		return
+	}
+
 	if output.KeyName == "" {
 		panic(fmt.Sprintf("invalid tag format: %s", tag))
 	}
 
+	if !validIdentifier(output.KeyName) {
+		panic(fmt.Sprintf("invalid column name %q: %s", output.KeyName, tag))
+	}
+
+	if output.JSON && output.Gob {
+		panic(fmt.Sprintf("json and gob are exclusive: %s", tag))
+	}
+
+	if onDelete != "" || onUpdate != "" {
+		if output.ForeignKey == nil {
+			panic(fmt.Sprintf("ondelete/onupdate require fkey: %s", tag))
+		}
+		output.ForeignKey.OnDelete = onDelete
+		output.ForeignKey.OnUpdate = onUpdate
+	}
+
 	return
 }
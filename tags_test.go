@@ -0,0 +1,150 @@
+package gomysql
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTagDefaultAndCheck(t *testing.T) {
+	opts := mustParseTag("status,notnull,default:'a,b',check:status IN ('a,b', 'c'),unique")
+
+	assert.Equal(t, "status", opts.KeyName)
+	assert.True(t, opts.NotNull)
+	assert.True(t, opts.Unique)
+	assert.Equal(t, "'a,b'", opts.Default)
+	assert.Equal(t, "status IN ('a,b', 'c')", opts.Check)
+
+	assert.Panics(t, func() { mustParseTag("status,default:") })
+	assert.Panics(t, func() { mustParseTag("status,check: ") })
+}
+
+func TestDefaultsMatch(t *testing.T) {
+	field := func(def string) RegisteredStructField {
+		return RegisteredStructField{Opts: SQLTagOpts{Default: def}}
+	}
+
+	cases := []struct {
+		existing sql.NullString
+		want     string
+		match    bool
+	}{
+		{sql.NullString{}, "", true},
+		{sql.NullString{String: "NULL", Valid: true}, "", true},
+		{sql.NullString{String: "'draft'", Valid: true}, "'draft'", true},
+		{sql.NullString{String: "draft", Valid: true}, "'draft'", true},
+		{sql.NullString{String: "'draft'::text", Valid: true}, "'draft'", true},
+		{sql.NullString{String: "'draft'", Valid: true}, "'live'", false},
+		{sql.NullString{String: "''", Valid: true}, "", false},
+		{sql.NullString{String: "0", Valid: true}, "0", true},
+		{sql.NullString{String: "1", Valid: true}, "0", false},
+		{sql.NullString{String: "current_timestamp()", Valid: true}, "CURRENT_TIMESTAMP", true},
+		{sql.NullString{String: "(datetime('now'))", Valid: true}, "(datetime('now'))", true},
+		{sql.NullString{String: "0", Valid: true}, "", false},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.match, defaultsMatch(c.existing, field(c.want)), "existing %+v, want %q", c.existing, c.want)
+	}
+
+	serial := RegisteredStructField{Opts: SQLTagOpts{AutoIncr: true}}
+	assert.True(t, defaultsMatch(sql.NullString{String: `nextval('"t_id_seq"'::regclass)`, Valid: true}, serial))
+}
+
+func TestParseTagForeignKeyActions(t *testing.T) {
+	opts := mustParseTag("user_id,ondelete:Set_Null,fkey:User.id,onupdate:cascade")
+
+	assert.Equal(t, &ForeignKeyRef{TableName: "User", ColumnName: "id", OnDelete: "SET NULL", OnUpdate: "CASCADE"}, opts.ForeignKey)
+	assert.Equal(t, `REFERENCES "User"("id") ON DELETE SET NULL ON UPDATE CASCADE`, foreignKeyReference(SQLite, opts.ForeignKey))
+
+	assert.Panics(t, func() { mustParseTag("user_id,fkey:User.id,ondelete:explode") })
+	assert.Panics(t, func() { mustParseTag("user_id,ondelete:cascade") })
+
+	info := &foreignKeyInfo{Table: "User", To: "id", OnDelete: "NO ACTION", OnUpdate: "NO ACTION"}
+	assert.True(t, foreignKeyRefsEqual(info, &ForeignKeyRef{TableName: "User", ColumnName: "id"}))
+	assert.False(t, foreignKeyRefsEqual(info, opts.ForeignKey))
+}
+
+func TestParseTagValidatesNames(t *testing.T) {
+	for _, tag := range []string{"order", "group,index:select", "from,fkey:archive.order.where", "_id$1"} {
+		assert.NotPanics(t, func() { mustParseTag(tag) }, tag)
+	}
+
+	for _, tag := range []string{"first name", "1st", "na\x00me", `a"b`, "id,index:bad name", "id,fkey:a.b.c.d", "id,fkey:.users.id"} {
+		assert.Panics(t, func() { mustParseTag(tag) }, tag)
+	}
+
+	assert.True(t, validTableName("archive.users"))
+	assert.False(t, validTableName("archive."))
+	assert.False(t, validTableName("a.b.c"))
+}
+
+func TestFilterQuotesIdentifiers(t *testing.T) {
+	field := &RegisteredStructField{Opts: SQLTagOpts{KeyName: "order"}, RealName: "Order", Type: reflect.TypeOf(0)}
+	filter := NewFilter().KeyCmp(field, OpGreaterThan, 1).Ordering(field, false)
+
+	fragment, _, err := filter.Build()
+	assert.NoError(t, err)
+	assert.Equal(t, `WHERE "order" > ? ORDER BY "order" DESC`, fragment)
+
+	fragment, _, err = filter.build(MySQL)
+	assert.NoError(t, err)
+	assert.Equal(t, "WHERE `order` > ? ORDER BY `order` DESC", fragment)
+
+	clause, _, err := buildUpdateAssignments(MySQL, []UpdateAssignment{SetAdd(field, 1)})
+	assert.NoError(t, err)
+	assert.Equal(t, "`order` = `order` + ?", clause)
+}
+
+func TestParseTagInline(t *testing.T) {
+	assert.Equal(t, SQLTagOpts{Inline: true}, mustParseTag(",inline"))
+	assert.Equal(t, SQLTagOpts{Inline: true, Prefix: "billing_"}, mustParseTag(",inline,prefix:billing_"))
+
+	for _, tag := range []string{"address,inline", ",inline,unique", ",prefix:x_", "name,prefix:x_", ",inline,prefix:1x"} {
+		assert.Panics(t, func() { mustParseTag(tag) }, tag)
+	}
+}
+
+func TestParseTagJSON(t *testing.T) {
+	assert.True(t, mustParseTag("doc,json").JSON)
+	assert.True(t, mustParseTag("doc,gob").Gob)
+	assert.Panics(t, func() { mustParseTag("doc,json,gob") })
+}
+
+func TestFilterJSONPaths(t *testing.T) {
+	field := &RegisteredStructField{Opts: SQLTagOpts{KeyName: "doc"}, InternalType: TypeRepJSON}
+	filter := NewFilter().JSONCmp(field, "$.address.lines[1]", OpEqual, "x")
+
+	for d, want := range map[Dialect]string{
+		SQLite:   `WHERE json_extract("doc", '$.address.lines[1]') = ?`,
+		MySQL:    "WHERE JSON_EXTRACT(`doc`, '$.address.lines[1]') = ?",
+		Postgres: `WHERE ("doc"::jsonb #>> '{address,lines,1}') = ?`,
+	} {
+		fragment, args, err := filter.build(d)
+		assert.NoError(t, err)
+		assert.Equal(t, want, fragment)
+		assert.Equal(t, []any{"x"}, args)
+	}
+
+	typed := NewFilter().
+		JSONCmp(field, "$.size", OpGreaterThan, 12).
+		And().
+		JSONCmp(field, "$.sizes", OpIn, []float64{1.5, 2}).
+		And().
+		JSONCmp(field, "$.active", OpEqual, true)
+
+	fragment, _, err := typed.build(Postgres)
+	assert.NoError(t, err)
+	assert.Equal(t, `WHERE ("doc"::jsonb #>> '{size}')::numeric > $1 AND ("doc"::jsonb #>> '{sizes}')::numeric IN ($2, $3) AND ("doc"::jsonb #>> '{active}')::boolean = $4`, Postgres.rebind(fragment))
+
+	fragment, _, err = typed.build(SQLite)
+	assert.NoError(t, err)
+	assert.Equal(t, `WHERE json_extract("doc", '$.size') > ? AND json_extract("doc", '$.sizes') IN (?, ?) AND json_extract("doc", '$.active') = ?`, fragment)
+
+	assert.Panics(t, func() { NewFilter().JSONCmp(field, "address", OpEqual, "x") })
+	assert.Panics(t, func() {
+		NewFilter().JSONCmp(&RegisteredStructField{Opts: SQLTagOpts{KeyName: "name"}, InternalType: TypeRepString}, "$.a", OpEqual, "x")
+	})
+}
@@ -0,0 +1,207 @@
+package test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/z46-dev/gomysql"
+)
+
+type Sale struct {
+	ID     int       `gomysql:"id,primary,increment"`
+	Region string    `gomysql:"region,index"`
+	Units  int       `gomysql:"units"`
+	Price  float64   `gomysql:"price"`
+	Sold   time.Time `gomysql:"sold"`
+	Note   string    `gomysql:"note"`
+}
+
+func TestAggregates(t *testing.T) {
+	driver := openTestDriver(t, ":memory:")
+
+	sales, err := gomysql.RegisterOn(driver, Sale{})
+	if err != nil {
+		t.Fatalf("failed to register sale: %v", err)
+	}
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, region := range []string{"north", "south", "north", "east", "south", "north"} {
+		if err := sales.Insert(&Sale{Region: region, Units: i + 1, Price: float64(i) + 0.5, Sold: start.AddDate(0, 0, i)}); err != nil {
+			t.Fatalf("failed to insert sale: %v", err)
+		}
+	}
+
+	var (
+		units  = sales.FieldByGoName("Units")
+		price  = sales.FieldByGoName("Price")
+		sold   = sales.FieldByGoName("Sold")
+		region = sales.FieldByGoName("Region")
+		north  = gomysql.NewFilter().KeyCmp(region, gomysql.OpEqual, "north")
+	)
+
+	if total, err := gomysql.Sum[int64](sales, units, nil); err != nil || total != 21 {
+		t.Fatalf("expected 21 units, got %d (%v)", total, err)
+	}
+
+	if total, err := gomysql.Sum[int](sales, units, north); err != nil || total != 1+3+6 {
+		t.Fatalf("expected 10 northern units, got %d (%v)", total, err)
+	}
+
+	if total, err := gomysql.Sum[float64](sales, price, nil); err != nil || total != 18 {
+		t.Fatalf("expected a price total of 18, got %v (%v)", total, err)
+	}
+
+	if avg, err := gomysql.Avg(sales, units, north); err != nil || math.Abs(avg-10.0/3) > 1e-9 {
+		t.Fatalf("expected an average of 10/3, got %v (%v)", avg, err)
+	}
+
+	if first, err := gomysql.Min[time.Time](sales, sold, nil); err != nil || !first.Equal(start) {
+		t.Fatalf("expected the first sale at %v, got %v (%v)", start, first, err)
+	}
+
+	if last, err := gomysql.Max[int](sales, units, gomysql.NewFilter().
+		Ordering(units, true).
+		Limit(3)); err != nil || last != 3 {
+		t.Fatalf("expected the largest of the three smallest to be 3, got %d (%v)", last, err)
+	}
+
+	none := gomysql.NewFilter().KeyCmp(region, gomysql.OpEqual, "west")
+	if missing, err := gomysql.Max[*int](sales, units, none); err != nil || missing != nil {
+		t.Fatalf("expected no maximum without rows, got %v (%v)", missing, err)
+	}
+	if total, err := gomysql.Sum[int64](sales, units, none); err != nil || total != 0 {
+		t.Fatalf("expected an empty sum of 0, got %d (%v)", total, err)
+	}
+
+	if _, err := gomysql.Sum[int64](sales, sales.FieldByGoName("Note"), nil); err == nil {
+		t.Fatalf("expected summing a text field to fail")
+	}
+}
+
+func TestGroupByAggregate(t *testing.T) {
+	driver := openTestDriver(t, ":memory:")
+
+	sales, err := gomysql.RegisterOn(driver, Sale{})
+	if err != nil {
+		t.Fatalf("failed to register sale: %v", err)
+	}
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, region := range []string{"north", "south", "north", "east", "south", "north"} {
+		if err := sales.Insert(&Sale{Region: region, Units: i + 1, Price: 2, Sold: start.AddDate(0, 0, i)}); err != nil {
+			t.Fatalf("failed to insert sale: %v", err)
+		}
+	}
+
+	var (
+		units  = sales.FieldByGoName("Units")
+		sold   = sales.FieldByGoName("Sold")
+		region = sales.FieldByGoName("Region")
+	)
+
+	rows, err := sales.GroupBy(region).
+		Having(gomysql.NewFilter().Ordering(region, true)).
+		Aggregate(gomysql.CountRows(), gomysql.SumOf(units), gomysql.AvgOf(sales.FieldByGoName("Price")), gomysql.MaxOf(sold))
+	if err != nil {
+		t.Fatalf("failed to aggregate: %v", err)
+	}
+
+	if len(rows) != 3 {
+		t.Fatalf("expected 3 regions, got %d", len(rows))
+	}
+
+	north := rows[1]
+	if north.Group.Region != "north" || north.Group.Units != 0 || north.Group.ID != 0 {
+		t.Fatalf("unexpected group %+v", north.Group)
+	}
+
+	if north.Values[0] != int64(3) || north.Values[1] != int64(10) || north.Values[2] != float64(2) {
+		t.Fatalf("unexpected aggregates %#v", north.Values)
+	}
+
+	if last, ok := north.Values[3].(time.Time); !ok || !last.Equal(start.AddDate(0, 0, 5)) {
+		t.Fatalf("expected the last northern sale time, got %#v", north.Values[3])
+	}
+
+	rows, err = sales.GroupBy(region).
+		Where(gomysql.NewFilter().KeyCmp(units, gomysql.OpGreaterThan, 1)).
+		Having(gomysql.NewFilter().
+			AggregateCmp(gomysql.CountRows(), gomysql.OpGreaterThanOrEqual, 2).
+			Or().
+			AggregateCmp(gomysql.MaxOf(units), gomysql.OpEqual, 4)).
+		Aggregate(gomysql.SumOf(units))
+	if err != nil {
+		t.Fatalf("failed to aggregate with having: %v", err)
+	}
+
+	totals := make(map[string]any)
+	for _, row := range rows {
+		totals[row.Group.Region] = row.Values[0]
+	}
+
+	// North keeps 3 and 6 after the where filter, south 2 and 5, and east its single 4.
+	if len(totals) != 3 || totals["north"] != int64(9) || totals["south"] != int64(7) || totals["east"] != int64(4) {
+		t.Fatalf("unexpected totals %v", totals)
+	}
+
+	rows, err = sales.GroupBy().Aggregate(gomysql.CountRows(), gomysql.MinOf(units))
+	if err != nil || len(rows) != 1 || rows[0].Values[0] != int64(6) || rows[0].Values[1] != 1 {
+		t.Fatalf("expected one group over every row, got %+v (%v)", rows, err)
+	}
+
+	if _, err := sales.GroupBy(region).Where(gomysql.NewFilter().Limit(1)).Aggregate(gomysql.CountRows()); err == nil {
+		t.Fatalf("expected a where filter with a limit to be rejected")
+	}
+
+	if _, err := sales.GroupBy(region).Aggregate(); err == nil {
+		t.Fatalf("expected a query without aggregates to be rejected")
+	}
+}
+
+type Reading struct {
+	ID    int    `gomysql:"id,primary,increment"`
+	Value uint64 `gomysql:"value"`
+}
+
+func TestAggregatesOverLargeUnsigned(t *testing.T) {
+	driver := openTestDriver(t, ":memory:")
+
+	readings, err := gomysql.RegisterOn(driver, Reading{})
+	if err != nil {
+		t.Fatalf("failed to register reading: %v", err)
+	}
+
+	for _, value := range []uint64{1, 2, math.MaxInt64 + 10} {
+		if err := readings.Insert(&Reading{Value: value}); err != nil {
+			t.Fatalf("failed to insert reading: %v", err)
+		}
+	}
+
+	value := readings.FieldByGoName("Value")
+
+	// The blob of the value above math.MaxInt64 sorts after the integers, so MIN and MAX are
+	// right.
+	if lo, err := gomysql.Min[uint64](readings, value, nil); err != nil || lo != 1 {
+		t.Fatalf("expected min 1, got %d (%v)", lo, err)
+	}
+
+	if hi, err := gomysql.Max[uint64](readings, value, nil); err != nil || hi != math.MaxInt64+10 {
+		t.Fatalf("expected max %d, got %d (%v)", uint64(math.MaxInt64+10), hi, err)
+	}
+
+	// SQLite's SUM and AVG read the blob as 0: AVG is silently wrong, and SUM comes back as a
+	// float that fails to decode.
+	if avg, err := gomysql.Avg(readings, value, nil); err != nil || avg != 1 {
+		t.Fatalf("expected SQLite to average the blob as 0, got %v (%v)", avg, err)
+	}
+
+	if _, err := gomysql.Sum[uint64](readings, value, nil); err == nil {
+		t.Fatalf("expected the sum to fail")
+	}
+
+	filter := gomysql.NewFilter().KeyCmp(value, gomysql.OpLessThanOrEqual, uint64(math.MaxInt64))
+	if sum, err := gomysql.Sum[uint64](readings, value, filter); err != nil || sum != 3 {
+		t.Fatalf("expected the values up to math.MaxInt64 to sum to 3, got %d (%v)", sum, err)
+	}
+}
@@ -2,6 +2,8 @@ package test
 
 import (
 	"fmt"
+	"path/filepath"
+	"runtime"
 	"testing"
 	"time"
 
@@ -45,6 +47,40 @@ func BenchmarkInsertSelect(b *testing.B) {
 	b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "ops/s")
 }
 
+func BenchmarkInsertMany(b *testing.B) {
+	if err := gomysql.Begin(":memory:"); err != nil {
+		b.Fatalf("failed to connect to database: %v", err)
+	}
+	defer func() {
+		if err := gomysql.Close(); err != nil {
+			b.Fatalf("failed to close database connection: %v", err)
+		}
+	}()
+
+	handler, err := gomysql.Register(Document{})
+	if err != nil {
+		b.Fatalf("failed to register Document struct: %v", err)
+	}
+
+	docs := make([]*Document, b.N)
+	for i := range docs {
+		docs[i] = &Document{
+			Title:        fmt.Sprintf("Bench Doc %d", i),
+			Body:         "Benchmark body",
+			Tags:         []string{"bench"},
+			Creation:     time.Now(),
+			BooleanField: i%2 == 0,
+		}
+	}
+
+	b.ResetTimer()
+	if err := handler.InsertMany(docs, gomysql.InsertManyOptions{}); err != nil {
+		b.Fatalf("failed to insert documents: %v", err)
+	}
+
+	b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "ops/s")
+}
+
 func BenchmarkSelectAllWithFilter(b *testing.B) {
 	if err := gomysql.Begin(":memory:"); err != nil {
 		b.Fatalf("failed to connect to database: %v", err)
@@ -131,3 +167,125 @@ func BenchmarkUpdateWithFilterReturning(b *testing.B) {
 
 	b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "ops/s")
 }
+
+func benchmarkParallelSelect(b *testing.B, opts gomysql.DriverOptions) {
+	driver, err := gomysql.Open(filepath.Join(b.TempDir(), "bench.db"), opts)
+	if err != nil {
+		b.Fatalf("failed to open database: %v", err)
+	}
+	defer func() {
+		if err := driver.Close(); err != nil {
+			b.Fatalf("failed to close database connection: %v", err)
+		}
+	}()
+
+	handler, err := gomysql.RegisterOn(driver, Document{})
+	if err != nil {
+		b.Fatalf("failed to register Document struct: %v", err)
+	}
+
+	const rows = 1000
+	for i := 0; i < rows; i++ {
+		doc := &Document{
+			Title:    fmt.Sprintf("Bench Doc %d", i),
+			Body:     "Benchmark body",
+			Tags:     []string{"bench"},
+			Creation: time.Now(),
+		}
+		if err := handler.Insert(doc); err != nil {
+			b.Fatalf("failed to insert document: %v", err)
+		}
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			if _, err := handler.Select(i%rows + 1); err != nil {
+				b.Errorf("failed to select document: %v", err)
+				return
+			}
+			i++
+		}
+	})
+
+	b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "ops/s")
+}
+
+func BenchmarkParallelSelectSingleConnection(b *testing.B) {
+	benchmarkParallelSelect(b, gomysql.DriverOptions{})
+}
+
+func BenchmarkParallelSelectWALReadPool(b *testing.B) {
+	benchmarkParallelSelect(b, gomysql.DriverOptions{WAL: true, ReadPoolSize: runtime.GOMAXPROCS(0)})
+}
+
+// benchmarkStatementCache runs a benchmark against an in-memory database with the prepared
+// statement cache on and off. Compare the two with benchstat over several -count runs; measured
+// results are listed under "Prepared statements" in docs/quickstart.md.
+func benchmarkStatementCache(b *testing.B, run func(b *testing.B, handler *gomysql.RegisteredStruct[Document])) {
+	for _, bench := range []struct {
+		name string
+		size int
+	}{
+		{"cached", 0},
+		{"uncached", -1},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			driver, err := gomysql.Open(":memory:", gomysql.DriverOptions{StatementCacheSize: bench.size})
+			if err != nil {
+				b.Fatalf("failed to open database: %v", err)
+			}
+			defer func() {
+				if err := driver.Close(); err != nil {
+					b.Fatalf("failed to close database connection: %v", err)
+				}
+			}()
+
+			handler, err := gomysql.RegisterOn(driver, Document{})
+			if err != nil {
+				b.Fatalf("failed to register Document struct: %v", err)
+			}
+
+			for i := 0; i < 100; i++ {
+				doc := &Document{
+					Title:    fmt.Sprintf("Bench Doc %d", i),
+					Body:     "Benchmark body",
+					Tags:     []string{"bench"},
+					Creation: time.Now(),
+				}
+				if err := handler.Insert(doc); err != nil {
+					b.Fatalf("failed to insert document: %v", err)
+				}
+			}
+
+			b.ResetTimer()
+			run(b, handler)
+			b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "ops/s")
+		})
+	}
+}
+
+func BenchmarkStatementCacheSelect(b *testing.B) {
+	benchmarkStatementCache(b, func(b *testing.B, handler *gomysql.RegisteredStruct[Document]) {
+		for i := 0; i < b.N; i++ {
+			if _, err := handler.Select(i%100 + 1); err != nil {
+				b.Fatalf("failed to select document: %v", err)
+			}
+		}
+	})
+}
+
+func BenchmarkStatementCacheFilter(b *testing.B) {
+	benchmarkStatementCache(b, func(b *testing.B, handler *gomysql.RegisteredStruct[Document]) {
+		for i := 0; i < b.N; i++ {
+			filter := gomysql.NewFilter().
+				KeyCmp(handler.FieldByGoName("ID"), gomysql.OpGreaterThan, i%100).
+				And().
+				KeyCmp(handler.FieldByGoName("Title"), gomysql.OpLike, "%Doc 9%")
+			if _, err := handler.CountWithFilter(filter); err != nil {
+				b.Fatalf("failed to count documents: %v", err)
+			}
+		}
+	})
+}
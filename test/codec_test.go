@@ -0,0 +1,252 @@
+package test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/z46-dev/gomysql"
+)
+
+// Level is stored as an integer scaled by ten through driver.Valuer and sql.Scanner.
+type Level int
+
+func (l Level) Value() (driver.Value, error) {
+	return int64(l) * 10, nil
+}
+
+func (l *Level) Scan(src any) error {
+	value, ok := src.(int64)
+	if !ok {
+		return fmt.Errorf("unexpected level %T", src)
+	}
+	*l = Level(value / 10)
+	return nil
+}
+
+type Point struct {
+	X, Y int
+}
+
+func init() {
+	gomysql.RegisterCodec(func(p Point) (driver.Value, error) {
+		return fmt.Sprintf("%d,%d", p.X, p.Y), nil
+	}, func(raw any) (Point, error) {
+		var p Point
+		text, _ := raw.(string)
+		_, err := fmt.Sscanf(text, "%d,%d", &p.X, &p.Y)
+		return p, err
+	}, "VARCHAR(64)")
+}
+
+type Host struct {
+	ID       int           `gomysql:"id,primary,increment"`
+	Addr     netip.Addr    `gomysql:"addr,unique"`
+	Previous *netip.Addr   `gomysql:"previous"`
+	Level    Level         `gomysql:"level"`
+	Location Point         `gomysql:"location"`
+	Note     sql.NullInt64 `gomysql:"note"`
+}
+
+// Marker is keyed by a registered codec type.
+type Marker struct {
+	At    Point  `gomysql:"at,primary"`
+	Label string `gomysql:"label"`
+}
+
+// Peer is keyed by a TextMarshaler type.
+type Peer struct {
+	Addr netip.Addr `gomysql:"addr,primary"`
+	Name string     `gomysql:"name"`
+}
+
+type HalfCodec struct {
+	ID    int       `gomysql:"id,primary,increment"`
+	Value halfValue `gomysql:"value"`
+}
+
+type halfValue struct {
+	text string
+}
+
+func (h halfValue) Value() (driver.Value, error) {
+	return h.text, nil
+}
+
+func TestCustomCodecs(t *testing.T) {
+	db, driver := openRawTestDriver(t, gomysql.DriverOptions{})
+
+	hosts, err := gomysql.RegisterOn(driver, Host{})
+	if err != nil {
+		t.Fatalf("failed to register host: %v", err)
+	}
+
+	previous := netip.MustParseAddr("10.0.0.1")
+	items := []*Host{
+		{Addr: netip.MustParseAddr("192.168.1.10"), Previous: &previous, Level: 3, Location: Point{1, 2}, Note: sql.NullInt64{Int64: 5, Valid: true}},
+		{Addr: netip.MustParseAddr("::1"), Level: 7, Location: Point{-4, 9}},
+	}
+	for _, item := range items {
+		if err := hosts.Insert(item); err != nil {
+			t.Fatalf("failed to insert host: %v", err)
+		}
+	}
+
+	var (
+		addr, location string
+		level          int64
+	)
+	if err := db.QueryRow("SELECT addr, level, location FROM Host WHERE id = ?;", items[0].ID).Scan(&addr, &level, &location); err != nil {
+		t.Fatalf("failed to read raw values: %v", err)
+	}
+
+	if addr != "192.168.1.10" || level != 30 || location != "1,2" {
+		t.Fatalf("unexpected stored values %q, %d, %q", addr, level, location)
+	}
+
+	var locationType string
+	if err := db.QueryRow("SELECT type FROM pragma_table_info('Host') WHERE name = 'location';").Scan(&locationType); err != nil || locationType != "VARCHAR(64)" {
+		t.Fatalf("expected the codec's column type, got %q (%v)", locationType, err)
+	}
+
+	got, err := hosts.Select(items[0].ID)
+	if err != nil || got == nil {
+		t.Fatalf("failed to select host: %v", err)
+	}
+
+	if got.Addr != items[0].Addr || got.Previous == nil || *got.Previous != previous || got.Level != 3 || got.Location != (Point{1, 2}) || got.Note != items[0].Note {
+		t.Fatalf("unexpected host: %+v", got)
+	}
+
+	if got, err = hosts.Select(items[1].ID); err != nil || got.Previous != nil || got.Note.Valid || got.Location != (Point{-4, 9}) {
+		t.Fatalf("unexpected host: %+v (%v)", got, err)
+	}
+
+	filtered, err := hosts.SelectAllWithFilter(gomysql.NewFilter().
+		KeyCmp(hosts.FieldByGoName("Addr"), gomysql.OpEqual, netip.MustParseAddr("::1")).
+		Or().
+		KeyCmp(hosts.FieldByGoName("Level"), gomysql.OpGreaterThan, Level(5)).
+		Or().
+		KeyCmp(hosts.FieldByGoName("Location"), gomysql.OpEqual, Point{-4, 9}))
+	if err != nil || len(filtered) != 1 || filtered[0].ID != items[1].ID {
+		t.Fatalf("expected the ::1 host, got %+v (%v)", filtered, err)
+	}
+
+	count, err := hosts.CountWithFilter(gomysql.NewFilter().
+		KeyCmp(hosts.FieldByGoName("Level"), gomysql.OpIn, []Level{3, 7}).
+		And().
+		KeyCmp(hosts.FieldByGoName("Previous"), gomysql.OpEqual, &previous))
+	if err != nil || count != 1 {
+		t.Fatalf("expected 1 host, got %d (%v)", count, err)
+	}
+
+	if _, err := hosts.UpdateWithFilter(
+		gomysql.NewFilter().KeyCmp(hosts.FieldByGoName("ID"), gomysql.OpEqual, items[1].ID),
+		gomysql.SetField(hosts.FieldByGoName("Location"), Point{5, 5}),
+	); err != nil {
+		t.Fatalf("failed to update location: %v", err)
+	}
+
+	if got, err = hosts.Select(items[1].ID); err != nil || got.Location != (Point{5, 5}) {
+		t.Fatalf("expected the updated location, got %+v (%v)", got, err)
+	}
+
+	if _, err := gomysql.RegisterOn(driver, HalfCodec{}); err == nil || !strings.Contains(err.Error(), "sql.Scanner") {
+		t.Fatalf("expected a Valuer without a Scanner to be rejected, got %v", err)
+	}
+}
+
+func TestCodecPrimaryKeys(t *testing.T) {
+	driver := openTestDriver(t, ":memory:")
+
+	markers, err := gomysql.RegisterOn(driver, Marker{})
+	if err != nil {
+		t.Fatalf("failed to register marker: %v", err)
+	}
+
+	for _, marker := range []*Marker{{At: Point{1, 2}, Label: "a"}, {At: Point{3, 4}, Label: "b"}} {
+		if err := markers.Insert(marker); err != nil {
+			t.Fatalf("failed to insert marker: %v", err)
+		}
+	}
+
+	if got, err := markers.Select(Point{1, 2}); err != nil || got == nil || got.Label != "a" {
+		t.Fatalf("expected marker a by its codec key, got %+v (%v)", got, err)
+	}
+
+	if found, err := gomysql.SelectMany(markers, []Point{{1, 2}, {3, 4}, {5, 6}}); err != nil || len(found) != 2 {
+		t.Fatalf("expected 2 markers by codec keys, got %+v (%v)", found, err)
+	}
+
+	if err := markers.Delete(Point{1, 2}); err != nil {
+		t.Fatalf("failed to delete marker by its codec key: %v", err)
+	}
+
+	if got, err := markers.Select(Point{1, 2}); err != nil || got != nil {
+		t.Fatalf("expected marker a to be deleted, got %+v (%v)", got, err)
+	}
+
+	peers, err := gomysql.RegisterOn(driver, Peer{})
+	if err != nil {
+		t.Fatalf("failed to register peer: %v", err)
+	}
+
+	local, remote := netip.MustParseAddr("127.0.0.1"), netip.MustParseAddr("fe80::1")
+	for _, peer := range []*Peer{{Addr: local, Name: "local"}, {Addr: remote, Name: "remote"}} {
+		if err := peers.Insert(peer); err != nil {
+			t.Fatalf("failed to insert peer: %v", err)
+		}
+	}
+
+	if got, err := peers.Select(remote); err != nil || got == nil || got.Name != "remote" {
+		t.Fatalf("expected the remote peer by its TextMarshaler key, got %+v (%v)", got, err)
+	}
+
+	if found, err := gomysql.SelectMany(peers, []netip.Addr{local, remote}); err != nil || len(found) != 2 {
+		t.Fatalf("expected 2 peers by TextMarshaler keys, got %+v (%v)", found, err)
+	}
+
+	if err := peers.Delete(local); err != nil {
+		t.Fatalf("failed to delete peer by its TextMarshaler key: %v", err)
+	}
+
+	if count, err := peers.Count(); err != nil || count != 1 {
+		t.Fatalf("expected 1 peer after deleting, got %d (%v)", count, err)
+	}
+}
+
+type Nickname struct {
+	Name  string `gomysql:"name,primary"`
+	Score int    `gomysql:"score"`
+}
+
+func TestIntegerArgumentOnStringKey(t *testing.T) {
+	driver := openTestDriver(t, ":memory:")
+
+	nicknames, err := gomysql.RegisterOn(driver, Nickname{})
+	if err != nil {
+		t.Fatalf("failed to register nickname: %v", err)
+	}
+
+	for _, nickname := range []*Nickname{{Name: "5", Score: 1}, {Name: "\x05", Score: 2}} {
+		if err := nicknames.Insert(nickname); err != nil {
+			t.Fatalf("failed to insert nickname: %v", err)
+		}
+	}
+
+	// The integer is bound as is, so it matches the text "5" rather than the rune 5.
+	if got, err := nicknames.Select(5); err != nil || got == nil || got.Name != "5" {
+		t.Fatalf("expected nickname \"5\" for an integer key, got %+v (%v)", got, err)
+	}
+
+	if err := nicknames.Delete(5); err != nil {
+		t.Fatalf("failed to delete nickname by an integer key: %v", err)
+	}
+
+	if rest, err := nicknames.SelectAll(); err != nil || len(rest) != 1 || rest[0].Name != "\x05" {
+		t.Fatalf("expected only the nickname \"\\x05\" to remain, got %+v (%v)", rest, err)
+	}
+}
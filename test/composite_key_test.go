@@ -0,0 +1,149 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/z46-dev/gomysql"
+)
+
+type UserRole struct {
+	UserID int    `gomysql:"user_id,primary"`
+	RoleID int    `gomysql:"role_id,primary"`
+	Note   string `gomysql:"note"`
+}
+
+type UserRoleKey struct {
+	UserID int
+	RoleID int
+}
+
+type UserRoleLink struct {
+	UserID int `gomysql:"user_id,primary"`
+	RoleID int `gomysql:"role_id,primary"`
+}
+
+type BadCompositeKey struct {
+	A int `gomysql:"a,primary,increment"`
+	B int `gomysql:"b,primary"`
+}
+
+func TestCompositeKeyCRUD(t *testing.T) {
+	handler, err := gomysql.RegisterOn(openTestDriver(t, ":memory:"), UserRole{})
+	if err != nil {
+		t.Fatalf("failed to register UserRole struct: %v", err)
+	}
+
+	assert.Len(t, handler.PrimaryKeyFields, 2)
+
+	for _, role := range []*UserRole{
+		{UserID: 1, RoleID: 1, Note: "admin"},
+		{UserID: 1, RoleID: 2, Note: "editor"},
+		{UserID: 2, RoleID: 1, Note: "admin"},
+	} {
+		if err := handler.Insert(role); err != nil {
+			t.Fatalf("failed to insert role: %v", err)
+		}
+	}
+
+	assert.Error(t, handler.Insert(&UserRole{UserID: 1, RoleID: 2}), "expected duplicate composite key to fail")
+
+	byTuple, err := handler.Select([]any{1, 2})
+	if err != nil || byTuple == nil {
+		t.Fatalf("failed to select by tuple: %v", err)
+	}
+	assert.Equal(t, UserRole{UserID: 1, RoleID: 2, Note: "editor"}, *byTuple)
+
+	byKeyStruct, err := handler.Select(UserRoleKey{UserID: 2, RoleID: 1})
+	if err != nil || byKeyStruct == nil {
+		t.Fatalf("failed to select by key struct: %v", err)
+	}
+	assert.Equal(t, "admin", byKeyStruct.Note)
+
+	byItem, err := handler.Select(&UserRole{UserID: 1, RoleID: 1})
+	if err != nil || byItem == nil {
+		t.Fatalf("failed to select by item: %v", err)
+	}
+	assert.Equal(t, "admin", byItem.Note)
+
+	_, err = handler.Select([]any{1})
+	assert.Error(t, err, "expected a short tuple to be rejected")
+
+	byTuple.Note = "writer"
+	if err := handler.Update(byTuple); err != nil {
+		t.Fatalf("failed to update role: %v", err)
+	}
+
+	updated, err := handler.Select([]any{1, 2})
+	if err != nil {
+		t.Fatalf("failed to select updated role: %v", err)
+	}
+	assert.Equal(t, "writer", updated.Note)
+
+	untouched, err := handler.Select([]any{1, 1})
+	if err != nil {
+		t.Fatalf("failed to select role: %v", err)
+	}
+	assert.Equal(t, "admin", untouched.Note, "expected update to match on every key column")
+
+	keys, err := handler.List()
+	if err != nil {
+		t.Fatalf("failed to list keys: %v", err)
+	}
+	assert.Len(t, keys, 3)
+	assert.EqualValues(t, []any{int64(1), int64(1)}, keys[0])
+
+	if err := handler.Delete(UserRoleKey{UserID: 1, RoleID: 1}); err != nil {
+		t.Fatalf("failed to delete role: %v", err)
+	}
+
+	deleted, err := handler.Select([]any{1, 1})
+	assert.NoError(t, err)
+	assert.Nil(t, deleted)
+
+	limited, err := handler.CountWithFilter(gomysql.NewFilter().Limit(1))
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, limited)
+
+	removed, err := handler.DeleteWithFilter(
+		gomysql.NewFilter().
+			KeyCmp(handler.FieldByGoName("UserID"), gomysql.OpEqual, 2).
+			Limit(1),
+	)
+	if err != nil {
+		t.Fatalf("failed to delete with limit: %v", err)
+	}
+	assert.EqualValues(t, 1, removed)
+
+	remaining, err := handler.SelectAll()
+	if err != nil {
+		t.Fatalf("failed to select remaining roles: %v", err)
+	}
+	assert.Len(t, remaining, 1)
+	assert.Equal(t, 1, remaining[0].UserID)
+}
+
+func TestCompositeKeyOnlyColumns(t *testing.T) {
+	handler, err := gomysql.RegisterOn(openTestDriver(t, ":memory:"), UserRoleLink{})
+	if err != nil {
+		t.Fatalf("failed to register UserRoleLink struct: %v", err)
+	}
+
+	link := &UserRoleLink{UserID: 3, RoleID: 4}
+	if err := handler.Insert(link); err != nil {
+		t.Fatalf("failed to insert link: %v", err)
+	}
+
+	assert.NoError(t, handler.Update(link), "expected update without value columns to be a no-op")
+
+	got, err := handler.Select([]any{3, 4})
+	if err != nil || got == nil {
+		t.Fatalf("failed to select link: %v", err)
+	}
+	assert.Equal(t, *link, *got)
+}
+
+func TestCompositeKeyRejectsAutoIncrement(t *testing.T) {
+	_, err := gomysql.RegisterOn(openTestDriver(t, ":memory:"), BadCompositeKey{})
+	assert.Error(t, err)
+}
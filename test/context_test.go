@@ -0,0 +1,76 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/z46-dev/gomysql"
+)
+
+func TestContextCancelledBeforeQuery(t *testing.T) {
+	driver := openTestDriver(t, ":memory:")
+
+	handler, err := gomysql.RegisterOn(driver, Document{})
+	if err != nil {
+		t.Fatalf("failed to register Document struct: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := handler.InsertCtx(ctx, &Document{Title: "cancelled"}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled from InsertCtx, got %v", err)
+	}
+
+	if _, err := handler.SelectAllWithFilterCtx(ctx, gomysql.NewFilter()); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled from SelectAllWithFilterCtx, got %v", err)
+	}
+
+	if count, err := handler.CountCtx(context.Background()); err != nil || count != 0 {
+		t.Fatalf("expected no rows after cancelled insert, got %d (%v)", count, err)
+	}
+}
+
+func TestContextDeadlineWhileWaitingForLock(t *testing.T) {
+	driver := openTestDriver(t, ":memory:")
+
+	handler, err := gomysql.RegisterOn(driver, Document{})
+	if err != nil {
+		t.Fatalf("failed to register Document struct: %v", err)
+	}
+
+	locked := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- driver.Tx(context.Background(), func(tx *gomysql.Tx) error {
+			close(locked)
+			<-release
+			return nil
+		})
+	}()
+
+	<-locked
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if _, err := handler.SelectCtx(ctx, 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded while waiting for lock, got %v", err)
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("transaction failed: %v", err)
+	}
+
+	if err := handler.InsertCtx(context.Background(), &Document{Title: "after"}); err != nil {
+		t.Fatalf("failed to insert after lock release: %v", err)
+	}
+
+	if _, err := handler.MigrateCtx(context.Background(), gomysql.MigrationOptions{}); err != nil {
+		t.Fatalf("failed to migrate with context: %v", err)
+	}
+}
@@ -0,0 +1,109 @@
+package test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/z46-dev/gomysql"
+)
+
+func openTestDriver(t *testing.T, path string) *gomysql.Driver {
+	t.Helper()
+
+	driver, err := gomysql.Open(path, gomysql.DriverOptions{})
+	if err != nil {
+		t.Fatalf("failed to open database %s: %v", path, err)
+	}
+
+	t.Cleanup(func() {
+		if err := driver.Close(); err != nil {
+			t.Fatalf("failed to close database %s: %v", path, err)
+		}
+	})
+
+	return driver
+}
+
+func TestIndependentDrivers(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	mainDriver := openTestDriver(t, filepath.Join(dir, "main.db"))
+	archiveDriver := openTestDriver(t, filepath.Join(dir, "archive.db"))
+
+	mainDocs, err := gomysql.RegisterOn(mainDriver, Document{})
+	if err != nil {
+		t.Fatalf("failed to register Document on main driver: %v", err)
+	}
+
+	archiveDocs, err := gomysql.RegisterOn(archiveDriver, Document{})
+	if err != nil {
+		t.Fatalf("failed to register Document on archive driver: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := mainDocs.Insert(&Document{Title: "main"}); err != nil {
+			t.Fatalf("failed to insert into main driver: %v", err)
+		}
+	}
+
+	if err := archiveDocs.Insert(&Document{Title: "archive"}); err != nil {
+		t.Fatalf("failed to insert into archive driver: %v", err)
+	}
+
+	mainCount, err := mainDocs.Count()
+	if err != nil {
+		t.Fatalf("failed to count main driver: %v", err)
+	}
+
+	archiveCount, err := archiveDocs.Count()
+	if err != nil {
+		t.Fatalf("failed to count archive driver: %v", err)
+	}
+
+	if mainCount != 3 || archiveCount != 1 {
+		t.Fatalf("expected counts 3 and 1, got %d and %d", mainCount, archiveCount)
+	}
+
+	archived, err := archiveDocs.Select(1)
+	if err != nil {
+		t.Fatalf("failed to select from archive driver: %v", err)
+	}
+
+	if archived == nil || archived.Title != "archive" {
+		t.Fatalf("expected archive document, got %+v", archived)
+	}
+}
+
+func TestParallelInMemoryDrivers(t *testing.T) {
+	for _, name := range []string{"first", "second", "third"} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			driver := openTestDriver(t, ":memory:")
+			handler, err := gomysql.RegisterOn(driver, Account{})
+			if err != nil {
+				t.Fatalf("failed to register Account: %v", err)
+			}
+
+			if err := handler.Insert(&Account{Username: name, Money: 10}); err != nil {
+				t.Fatalf("failed to insert account: %v", err)
+			}
+
+			accounts, err := handler.SelectAll()
+			if err != nil {
+				t.Fatalf("failed to select accounts: %v", err)
+			}
+
+			if len(accounts) != 1 || accounts[0].Username != name {
+				t.Fatalf("expected only %s in its own database, got %+v", name, accounts)
+			}
+		})
+	}
+}
+
+func TestRegisterOnNilDriver(t *testing.T) {
+	if _, err := gomysql.RegisterOn[Account](nil, Account{}); err != gomysql.ErrDatabaseNotInitialized {
+		t.Fatalf("expected ErrDatabaseNotInitialized, got %v", err)
+	}
+}
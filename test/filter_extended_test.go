@@ -115,3 +115,35 @@ func TestFilterNotIn(t *testing.T) {
 
 	assert.Len(t, results, 2, "expected two documents after not-in filter")
 }
+
+func TestFilterBuildBindsTimes(t *testing.T) {
+	db, driver := openRawTestDriver(t, gomysql.DriverOptions{})
+
+	handler, err := gomysql.RegisterOn(driver, Document{})
+	if err != nil {
+		t.Fatalf("failed to register Document struct: %v", err)
+	}
+
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i := range 3 {
+		if err := handler.Insert(&Document{Title: "doc", Creation: start.Add(time.Duration(i) * time.Hour)}); err != nil {
+			t.Fatalf("failed to insert document %d: %v", i, err)
+		}
+	}
+
+	// Build's output is run on the caller's own connection, so the time must already be
+	// formatted like the stored values.
+	fragment, args, err := gomysql.NewFilter().
+		KeyCmp(handler.FieldByGoName("Creation"), gomysql.OpGreaterThanOrEqual, start.Add(time.Hour)).
+		Build()
+	if err != nil {
+		t.Fatalf("failed to build filter: %v", err)
+	}
+
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM \"Document\" "+fragment+";", args...).Scan(&count); err != nil {
+		t.Fatalf("failed to run built filter: %v", err)
+	}
+
+	assert.Equal(t, 2, count)
+}
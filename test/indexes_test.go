@@ -0,0 +1,169 @@
+package test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/z46-dev/gomysql"
+	v1 "github.com/z46-dev/gomysql/test/migrationv1"
+	v2 "github.com/z46-dev/gomysql/test/migrationv2"
+)
+
+type Ticket struct {
+	ID    int    `gomysql:"id,primary,increment"`
+	Email string `gomysql:"email"`
+	Open  bool   `gomysql:"open,index"`
+}
+
+func (Ticket) Indexes() []gomysql.Index {
+	return []gomysql.Index{{Name: "ticket_open_email", Columns: []string{"email"}, Unique: true, Where: "open = 1"}}
+}
+
+type BadIndexTicket struct {
+	ID int `gomysql:"id,primary,increment"`
+}
+
+func (BadIndexTicket) Indexes() []gomysql.Index {
+	return []gomysql.Index{{Name: "bad_idx", Columns: []string{"missing"}}}
+}
+
+func TestIndexDeclarations(t *testing.T) {
+	withTestDB(t, func() {
+		handler, err := gomysql.Register(Ticket{})
+		if err != nil {
+			t.Fatalf("failed to register ticket: %v", err)
+		}
+
+		expected := []gomysql.Index{
+			{Name: "Ticket_open_idx", Columns: []string{"open"}},
+			{Name: "ticket_open_email", Columns: []string{"email"}, Unique: true, Where: "open = 1"},
+		}
+		if got := handler.Indexes(); !reflect.DeepEqual(got, expected) {
+			t.Fatalf("unexpected indexes:\n got: %+v\nwant: %+v", got, expected)
+		}
+
+		if err := handler.Insert(&Ticket{Email: "a@example.com", Open: true}); err != nil {
+			t.Fatalf("failed to insert open ticket: %v", err)
+		}
+
+		if err := handler.Insert(&Ticket{Email: "a@example.com", Open: false}); err != nil {
+			t.Fatalf("expected closed ticket to be outside the partial index: %v", err)
+		}
+
+		if err := handler.Insert(&Ticket{Email: "a@example.com", Open: true}); err == nil {
+			t.Fatalf("expected partial unique index to reject a second open ticket")
+		}
+
+		report, err := handler.Migrate(gomysql.MigrationOptions{})
+		if err != nil || len(report.AddedIndexes) != 0 || len(report.DroppedIndexes) != 0 {
+			t.Fatalf("expected indexes created on registration to match, got %+v (%v)", report, err)
+		}
+
+		if _, err := gomysql.Register(BadIndexTicket{}); err == nil {
+			t.Fatalf("expected index on an unknown column to be rejected")
+		}
+	})
+}
+
+func TestMigrationIndexes(t *testing.T) {
+	withTestDB(t, func() {
+		if _, err := gomysql.Register(v1.IndexItem{}); err != nil {
+			t.Fatalf("failed to register v1 struct: %v", err)
+		}
+
+		v2Handler, err := gomysql.Register(v2.IndexItem{})
+		if err != nil {
+			t.Fatalf("failed to register v2 struct: %v", err)
+		}
+
+		report, err := v2Handler.Migrate(gomysql.MigrationOptions{})
+		if !errors.Is(err, gomysql.ErrMigrationDestructive) {
+			t.Fatalf("expected dropping an index to require AllowDestructive, got %v", err)
+		}
+
+		if !reflect.DeepEqual(report.DroppedIndexes, []string{"IndexItem_name_idx"}) {
+			t.Fatalf("expected v1 name index to be reported as dropped, got %+v", report)
+		}
+
+		report, err = v2Handler.Migrate(gomysql.MigrationOptions{AllowDestructive: true})
+		if err != nil {
+			t.Fatalf("failed to migrate indexes: %v", err)
+		}
+
+		if !reflect.DeepEqual(report.DroppedIndexes, []string{"IndexItem_name_idx"}) || len(report.AddedIndexes) != 0 {
+			t.Fatalf("expected only the v1 index to be dropped, got %+v", report)
+		}
+
+		report, err = v2Handler.Migrate(gomysql.MigrationOptions{})
+		if err != nil || len(report.AddedIndexes) != 0 || len(report.DroppedIndexes) != 0 {
+			t.Fatalf("expected no index changes after migrating, got %+v (%v)", report, err)
+		}
+	})
+}
+
+func TestMigrationKeepsManualIndexes(t *testing.T) {
+	db, driver := openRawTestDriver(t, gomysql.DriverOptions{})
+
+	if _, err := gomysql.RegisterOn(driver, v1.CheckItem{}); err != nil {
+		t.Fatalf("failed to register v1 struct: %v", err)
+	}
+
+	for _, statement := range []string{
+		`CREATE INDEX "manual_priority" ON "CheckItem" ("priority");`,
+		`CREATE INDEX "manual_double" ON "CheckItem" ("priority" * 2);`,
+		`CREATE INDEX "CheckItem_manual_idx" ON "CheckItem" ("priority", "id");`,
+		`CREATE UNIQUE INDEX "CheckItem_priority_unique" ON "CheckItem" ("priority");`,
+	} {
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatalf("failed to create manual index: %v", err)
+		}
+	}
+
+	v2Handler, err := gomysql.RegisterOn(driver, v2.CheckItem{})
+	if err != nil {
+		t.Fatalf("failed to register v2 struct: %v", err)
+	}
+
+	// The CHECK change rebuilds the table, which recreates the manual column indexes but cannot
+	// recreate the expression index. CheckItem_manual_idx follows the naming of generated indexes
+	// but no column is called manual, so it is kept. The unique index left by a priority field
+	// that is no longer unique is dropped.
+	report, err := v2Handler.Migrate(gomysql.MigrationOptions{AllowDestructive: true})
+	if err != nil || !report.Rebuilt {
+		t.Fatalf("expected a rebuild, got %+v (%v)", report, err)
+	}
+
+	if !reflect.DeepEqual(report.DroppedIndexes, []string{"CheckItem_priority_unique", "manual_double"}) || len(report.AddedIndexes) != 0 {
+		t.Fatalf("expected the stale unique and expression indexes to be dropped, got %+v", report)
+	}
+
+	var names []string
+	rows, err := db.Query(`SELECT name FROM sqlite_master WHERE type = 'index' AND name NOT LIKE 'sqlite_%' ORDER BY name;`)
+	if err != nil {
+		t.Fatalf("failed to list indexes: %v", err)
+	}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			t.Fatalf("failed to scan index name: %v", err)
+		}
+		names = append(names, name)
+	}
+	rows.Close()
+
+	if !reflect.DeepEqual(names, []string{"CheckItem_manual_idx", "manual_priority"}) {
+		t.Fatalf("expected the manual indexes to survive the rebuild, got %v", names)
+	}
+
+	// Two priorities may now be equal.
+	if _, err := db.Exec(`INSERT INTO "CheckItem" ("priority") VALUES (5), (5);`); err != nil {
+		t.Fatalf("expected the unique constraint to be gone: %v", err)
+	}
+
+	// Without changes the manual index is neither dropped nor a reason to require AllowDestructive.
+	report, err = v2Handler.Migrate(gomysql.MigrationOptions{})
+	if err != nil || len(report.DroppedIndexes) != 0 {
+		t.Fatalf("expected the manual indexes to be kept, got %+v (%v)", report, err)
+	}
+}
@@ -0,0 +1,135 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/z46-dev/gomysql"
+)
+
+type Audit struct {
+	CreatedAt time.Time `gomysql:"created_at,index"`
+	UpdatedAt time.Time `gomysql:"updated_at"`
+	CreatedBy string    `gomysql:"created_by"`
+	internal  int
+}
+
+type Address struct {
+	Street string `gomysql:"street"`
+	City   string `gomysql:"city"`
+}
+
+type Customer struct {
+	ID       int     `gomysql:"id,primary,increment"`
+	Name     string  `gomysql:"name"`
+	Billing  Address `gomysql:",inline,prefix:billing_"`
+	Shipping Address `gomysql:",inline,prefix:shipping_"`
+	Audit
+}
+
+type DuplicateColumnCustomer struct {
+	ID      int    `gomysql:"id,primary,increment"`
+	City    string `gomysql:"city"`
+	Address `gomysql:",inline"`
+}
+
+type BadInlineCustomer struct {
+	ID   int    `gomysql:"id,primary,increment"`
+	Name string `gomysql:",inline"`
+}
+
+func TestInlineStructs(t *testing.T) {
+	withTestDB(t, func() {
+		customers, err := gomysql.Register(Customer{})
+		if err != nil {
+			t.Fatalf("failed to register customer: %v", err)
+		}
+
+		var columns []string
+		for _, field := range customers.Fields {
+			columns = append(columns, field.Opts.KeyName)
+		}
+
+		expected := []string{"id", "name", "billing_street", "billing_city", "shipping_street", "shipping_city", "created_at", "updated_at", "created_by"}
+		if len(columns) != len(expected) {
+			t.Fatalf("expected columns %v, got %v", expected, columns)
+		}
+		for i := range expected {
+			if columns[i] != expected[i] {
+				t.Fatalf("expected columns %v, got %v", expected, columns)
+			}
+		}
+
+		if field := customers.FieldByGoName("Billing.City"); field == nil || field.Opts.KeyName != "billing_city" {
+			t.Fatalf("expected Billing.City to map to billing_city, got %+v", field)
+		}
+
+		if dotted, promoted := customers.FieldByGoName("Audit.CreatedBy"), customers.FieldByGoName("CreatedBy"); dotted == nil || promoted == nil || dotted.Opts.KeyName != promoted.Opts.KeyName {
+			t.Fatalf("expected Audit.CreatedBy and CreatedBy to find the same field, got %+v and %+v", dotted, promoted)
+		}
+
+		if customers.FieldByGoName("City") != nil {
+			t.Fatalf("expected City of an inline field not to be promoted")
+		}
+
+		created := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+		items := []*Customer{
+			{Name: "ada", Billing: Address{Street: "1 Main", City: "Oslo"}, Shipping: Address{City: "Bergen"}, Audit: Audit{CreatedAt: created, CreatedBy: "admin"}},
+			{Name: "bob", Billing: Address{City: "Rome"}, Audit: Audit{CreatedAt: created.Add(time.Hour), CreatedBy: "system"}},
+		}
+		for _, item := range items {
+			if err := customers.Insert(item); err != nil {
+				t.Fatalf("failed to insert customer: %v", err)
+			}
+		}
+
+		got, err := customers.Select(items[0].ID)
+		if err != nil || got == nil {
+			t.Fatalf("failed to select customer: %v", err)
+		}
+
+		if got.Billing != items[0].Billing || got.Shipping != items[0].Shipping || got.CreatedBy != "admin" || !got.CreatedAt.Equal(created) {
+			t.Fatalf("unexpected customer: %+v", got)
+		}
+
+		filtered, err := customers.SelectAllWithFilter(gomysql.NewFilter().
+			KeyCmp(customers.FieldByGoName("Billing.City"), gomysql.OpEqual, "Rome").
+			Or().
+			KeyCmp(customers.FieldByGoName("CreatedAt"), gomysql.OpGreaterThan, created.Add(time.Minute)))
+		if err != nil {
+			t.Fatalf("failed to filter by inline columns: %v", err)
+		}
+
+		if len(filtered) != 1 || filtered[0].Name != "bob" {
+			t.Fatalf("expected bob, got %+v", filtered)
+		}
+
+		updated, err := customers.UpdateWithFilter(
+			gomysql.NewFilter().KeyCmp(customers.FieldByGoName("Audit.CreatedBy"), gomysql.OpEqual, "system"),
+			gomysql.SetField(customers.FieldByGoName("Shipping.City"), "Milan"),
+		)
+		if err != nil || updated != 1 {
+			t.Fatalf("expected 1 updated customer, got %d (%v)", updated, err)
+		}
+
+		if got, err = customers.Select(items[1].ID); err != nil || got.Shipping.City != "Milan" {
+			t.Fatalf("expected shipping city Milan, got %+v (%v)", got, err)
+		}
+
+		if indexes := customers.Indexes(); len(indexes) != 1 || indexes[0].Columns[0] != "created_at" {
+			t.Fatalf("expected an index on created_at, got %+v", indexes)
+		}
+	})
+}
+
+func TestInlineStructErrors(t *testing.T) {
+	withTestDB(t, func() {
+		if _, err := gomysql.Register(DuplicateColumnCustomer{}); err == nil {
+			t.Fatalf("expected a duplicate inline column to be rejected")
+		}
+
+		if _, err := gomysql.Register(BadInlineCustomer{}); err == nil {
+			t.Fatalf("expected an inline non-struct field to be rejected")
+		}
+	})
+}
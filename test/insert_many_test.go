@@ -0,0 +1,185 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/z46-dev/gomysql"
+)
+
+func TestInsertManyBackfillsKeys(t *testing.T) {
+	handler, err := gomysql.RegisterOn(openTestDriver(t, ":memory:"), Document{})
+	if err != nil {
+		t.Fatalf("failed to register Document struct: %v", err)
+	}
+
+	docs := make([]*Document, 2500)
+	for i := range docs {
+		docs[i] = &Document{
+			Title:    fmt.Sprintf("Doc #%4X", i),
+			Body:     "batch insert",
+			Tags:     []string{"batch"},
+			Creation: time.Now().UTC().Truncate(time.Second),
+		}
+	}
+
+	if err := handler.InsertMany(docs, gomysql.InsertManyOptions{BatchSize: 300}); err != nil {
+		t.Fatalf("failed to insert documents: %v", err)
+	}
+
+	primaryKeys, err := handler.List()
+	if err != nil {
+		t.Fatalf("failed to list primary keys: %v", err)
+	}
+
+	assert.Len(t, primaryKeys, len(docs))
+	for i, pk := range primaryKeys {
+		assert.EqualValues(t, docs[i].ID, pk, "primary key mismatch at index %d", i)
+	}
+
+	stored, err := handler.Select(docs[1234].ID)
+	if err != nil {
+		t.Fatalf("failed to select document: %v", err)
+	}
+	assert.Equal(t, docs[1234].Title, stored.Title)
+}
+
+func TestInsertManyRollsBackOnError(t *testing.T) {
+	handler, err := gomysql.RegisterOn(openTestDriver(t, ":memory:"), UpsertTeam{})
+	if err != nil {
+		t.Fatalf("failed to register UpsertTeam struct: %v", err)
+	}
+
+	items := []*UpsertTeam{{Name: "red"}, {Name: "blue"}, {Name: "red"}, {Name: "green"}}
+	err = handler.InsertMany(items, gomysql.InsertManyOptions{})
+
+	var manyErr *gomysql.InsertManyError
+	if !errors.As(err, &manyErr) {
+		t.Fatalf("expected InsertManyError, got %v", err)
+	}
+	assert.Len(t, manyErr.Items, 1)
+	assert.Equal(t, 2, manyErr.Items[0].Index)
+
+	count, err := handler.Count()
+	if err != nil {
+		t.Fatalf("failed to count teams: %v", err)
+	}
+	assert.EqualValues(t, 0, count, "expected the batch to be rolled back")
+
+	for _, item := range items {
+		assert.Zero(t, item.ID, "expected no key for a rolled back item")
+	}
+}
+
+func TestInsertManyContinueOnError(t *testing.T) {
+	handler, err := gomysql.RegisterOn(openTestDriver(t, ":memory:"), UpsertTeam{})
+	if err != nil {
+		t.Fatalf("failed to register UpsertTeam struct: %v", err)
+	}
+
+	items := []*UpsertTeam{{Name: "red"}, nil, {Name: "blue"}, {Name: "red"}, {Name: "green"}}
+	err = handler.InsertMany(items, gomysql.InsertManyOptions{ContinueOnError: true})
+
+	var manyErr *gomysql.InsertManyError
+	if !errors.As(err, &manyErr) {
+		t.Fatalf("expected InsertManyError, got %v", err)
+	}
+
+	var indexes []int
+	for _, item := range manyErr.Items {
+		indexes = append(indexes, item.Index)
+	}
+	assert.Equal(t, []int{1, 3}, indexes)
+
+	teams, err := handler.SelectAll()
+	if err != nil {
+		t.Fatalf("failed to select teams: %v", err)
+	}
+	assert.Len(t, teams, 3)
+
+	for _, i := range []int{0, 2, 4} {
+		stored, err := handler.Select(items[i].ID)
+		if err != nil {
+			t.Fatalf("failed to select team %d: %v", i, err)
+		}
+		assert.Equal(t, items[i].Name, stored.Name)
+	}
+	assert.Zero(t, items[3].ID)
+}
+
+func TestInsertManySecondaryIncrement(t *testing.T) {
+	handler, err := gomysql.RegisterOn(openTestDriver(t, ":memory:"), SecondaryKeyIncrementDoc{})
+	if err != nil {
+		t.Fatalf("failed to register SecondaryKeyIncrementDoc struct: %v", err)
+	}
+
+	if err := handler.Insert(&SecondaryKeyIncrementDoc{Name: "first"}); err != nil {
+		t.Fatalf("failed to insert document: %v", err)
+	}
+
+	items := []*SecondaryKeyIncrementDoc{{Name: "second"}, {Name: "third"}, {Name: "fourth"}}
+	if err := handler.InsertMany(items, gomysql.InsertManyOptions{}); err != nil {
+		t.Fatalf("failed to insert documents: %v", err)
+	}
+
+	for i, item := range items {
+		assert.Equal(t, i+2, item.Age)
+	}
+}
+
+func TestInsertManyResetsKeysOnRollback(t *testing.T) {
+	driver := openTestDriver(t, ":memory:")
+
+	handler, err := gomysql.RegisterOn(driver, Document{})
+	if err != nil {
+		t.Fatalf("failed to register Document struct: %v", err)
+	}
+
+	abort := errors.New("abort")
+	docs := []*Document{{Title: "a"}, {Title: "b"}}
+	if err := driver.Tx(context.Background(), func(tx *gomysql.Tx) error {
+		if err := handler.WithTx(tx).InsertMany(docs, gomysql.InsertManyOptions{}); err != nil {
+			return err
+		}
+		if docs[0].ID == 0 || docs[1].ID == 0 {
+			t.Fatalf("expected keys inside the transaction, got %d and %d", docs[0].ID, docs[1].ID)
+		}
+		return abort
+	}); !errors.Is(err, abort) {
+		t.Fatalf("expected the transaction to abort, got %v", err)
+	}
+
+	if docs[0].ID != 0 || docs[1].ID != 0 {
+		t.Fatalf("expected keys to be reset after rollback, got %d and %d", docs[0].ID, docs[1].ID)
+	}
+
+	kept := []*Document{{Title: "kept"}}
+	if err := driver.Tx(context.Background(), func(tx *gomysql.Tx) error {
+		if err := handler.WithTx(tx).InsertMany(kept, gomysql.InsertManyOptions{}); err != nil {
+			return err
+		}
+
+		// Rolling back a savepoint resets only the keys assigned inside it.
+		_ = tx.Savepoint(func(tx *gomysql.Tx) error {
+			if err := handler.WithTx(tx).InsertMany(docs, gomysql.InsertManyOptions{}); err != nil {
+				return err
+			}
+			return abort
+		})
+		return nil
+	}); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+
+	if kept[0].ID == 0 || docs[0].ID != 0 {
+		t.Fatalf("expected only the committed key to stay, got %d, %d", kept[0].ID, docs[0].ID)
+	}
+
+	if stored, err := handler.Select(kept[0].ID); err != nil || stored == nil || stored.Title != "kept" {
+		t.Fatalf("expected the committed document, got %+v (%v)", stored, err)
+	}
+}
@@ -0,0 +1,169 @@
+package test
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"github.com/z46-dev/gomysql"
+)
+
+type IntegerWidths struct {
+	ID   uint64  `gomysql:"id,primary,increment"`
+	I    int     `gomysql:"i"`
+	I8   int8    `gomysql:"i8"`
+	I16  int16   `gomysql:"i16"`
+	I32  int32   `gomysql:"i32"`
+	I64  int64   `gomysql:"i64"`
+	U    uint    `gomysql:"u"`
+	U8   uint8   `gomysql:"u8"`
+	U16  uint16  `gomysql:"u16"`
+	U32  uint32  `gomysql:"u32"`
+	U64  uint64  `gomysql:"u64"`
+	PU64 *uint64 `gomysql:"pu64"`
+}
+
+type UnsignedKey struct {
+	ID    uint32 `gomysql:"id,primary,increment"`
+	Name  string `gomysql:"name"`
+	Count uint16 `gomysql:"count"`
+}
+
+func TestIntegerWidthsRoundTrip(t *testing.T) {
+	_, driver := openRawTestDriver(t, gomysql.DriverOptions{})
+
+	widths, err := gomysql.RegisterOn(driver, IntegerWidths{})
+	if err != nil {
+		t.Fatalf("failed to register integer widths: %v", err)
+	}
+
+	roundTrip := func(item IntegerWidths) bool {
+		item.ID = 0
+		if err := widths.Insert(&item); err != nil {
+			t.Logf("failed to insert %+v: %v", item, err)
+			return false
+		}
+
+		got, err := widths.Select(item.ID)
+		if err != nil || got == nil {
+			t.Logf("failed to select %d: %v", item.ID, err)
+			return false
+		}
+
+		if (got.PU64 == nil) != (item.PU64 == nil) || (got.PU64 != nil && *got.PU64 != *item.PU64) {
+			t.Logf("pointer mismatch for %d", item.ID)
+			return false
+		}
+		got.PU64, item.PU64 = nil, nil
+
+		if *got != item {
+			t.Logf("expected %+v, got %+v", item, *got)
+			return false
+		}
+		return true
+	}
+
+	if err := quick.Check(roundTrip, &quick.Config{MaxCount: 200}); err != nil {
+		t.Fatal(err)
+	}
+
+	maxUint64 := uint64(math.MaxUint64)
+	boundaries := []IntegerWidths{
+		{I: math.MinInt, I8: math.MinInt8, I16: math.MinInt16, I32: math.MinInt32, I64: math.MinInt64},
+		{I: math.MaxInt, I8: math.MaxInt8, I16: math.MaxInt16, I32: math.MaxInt32, I64: math.MaxInt64},
+		{U: math.MaxUint, U8: math.MaxUint8, U16: math.MaxUint16, U32: math.MaxUint32, U64: math.MaxUint64, PU64: &maxUint64},
+		{U: math.MaxInt64 + 1, U64: math.MaxInt64 + 1},
+		{U64: math.MaxInt64},
+	}
+	for _, item := range boundaries {
+		if !roundTrip(item) {
+			t.Fatalf("boundary values did not round-trip: %+v", item)
+		}
+	}
+
+	large, err := widths.SelectAllWithFilter(gomysql.NewFilter().
+		KeyCmp(widths.FieldByGoName("U64"), gomysql.OpGreaterThan, uint64(math.MaxInt64)).
+		Ordering(widths.FieldByGoName("U64"), true))
+	if err != nil {
+		t.Fatalf("failed to filter large values: %v", err)
+	}
+
+	var values []uint64
+	for _, item := range large {
+		if item.U64 <= math.MaxInt64 {
+			t.Fatalf("unexpected value %d above MaxInt64", item.U64)
+		}
+		values = append(values, item.U64)
+	}
+	if !slices.IsSorted(values) || !slices.Contains(values, math.MaxUint64) || !slices.Contains(values, math.MaxInt64+1) {
+		t.Fatalf("expected the large values in order, got %v", values)
+	}
+
+	count, err := widths.CountWithFilter(gomysql.NewFilter().
+		KeyCmp(widths.FieldByGoName("U64"), gomysql.OpEqual, uint64(math.MaxUint64)).
+		And().
+		KeyCmp(widths.FieldByGoName("PU64"), gomysql.OpEqual, &maxUint64))
+	if err != nil || count != 1 {
+		t.Fatalf("expected 1 row with MaxUint64, got %d (%v)", count, err)
+	}
+}
+
+func TestUnsignedAutoIncrementKey(t *testing.T) {
+	_, driver := openRawTestDriver(t, gomysql.DriverOptions{})
+
+	keys, err := gomysql.RegisterOn(driver, UnsignedKey{})
+	if err != nil {
+		t.Fatalf("failed to register unsigned key: %v", err)
+	}
+
+	first, second := &UnsignedKey{Name: "first"}, &UnsignedKey{Name: "second"}
+	if err := keys.Insert(first); err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+	if err := keys.InsertMany([]*UnsignedKey{second}, gomysql.InsertManyOptions{}); err != nil {
+		t.Fatalf("failed to insert many: %v", err)
+	}
+
+	if first.ID != 1 || second.ID != 2 {
+		t.Fatalf("expected keys 1 and 2, got %d and %d", first.ID, second.ID)
+	}
+
+	if got, err := keys.Select(second.ID); err != nil || got == nil || got.Name != "second" {
+		t.Fatalf("failed to select by unsigned key: %+v (%v)", got, err)
+	}
+}
+
+func TestIntegerOverflow(t *testing.T) {
+	db, driver := openRawTestDriver(t, gomysql.DriverOptions{})
+
+	keys, err := gomysql.RegisterOn(driver, UnsignedKey{})
+	if err != nil {
+		t.Fatalf("failed to register unsigned key: %v", err)
+	}
+
+	if _, err := db.Exec("INSERT INTO UnsignedKey (id, name, count) VALUES (1, 'wide', 70000), (2, 'negative', -1);"); err != nil {
+		t.Fatalf("failed to insert raw rows: %v", err)
+	}
+
+	for _, id := range []uint32{1, 2} {
+		if _, err := keys.Select(id); err == nil || !strings.Contains(err.Error(), "overflows") {
+			t.Fatalf("expected an overflow error for row %d, got %v", id, err)
+		}
+	}
+
+	if _, err := db.Exec("INSERT INTO UnsignedKey (id, name, count) VALUES (4294967295, 'last', 0);"); err != nil {
+		t.Fatalf("failed to insert the last key: %v", err)
+	}
+	if err := keys.Insert(&UnsignedKey{Name: "next"}); err == nil || !strings.Contains(err.Error(), "overflows") {
+		t.Fatalf("expected the next key to overflow uint32, got %v", err)
+	}
+
+	defer func() {
+		if recovered := recover(); recovered == nil || !strings.Contains(recovered.(string), "overflows") {
+			t.Fatalf("expected an overflow panic, got %v", recovered)
+		}
+	}()
+	gomysql.NewFilter().KeyCmp(keys.FieldByGoName("Count"), gomysql.OpEqual, 70000)
+}
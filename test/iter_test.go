@@ -0,0 +1,204 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/z46-dev/gomysql"
+)
+
+func TestIterStreamsRows(t *testing.T) {
+	driver := openTestDriver(t, ":memory:")
+
+	handler, err := gomysql.RegisterOn(driver, Document{})
+	if err != nil {
+		t.Fatalf("failed to register Document struct: %v", err)
+	}
+
+	for i := range 100 {
+		if err := handler.Insert(&Document{Title: fmt.Sprintf("doc %03d", i), BooleanField: i%2 == 0}); err != nil {
+			t.Fatalf("failed to insert document %d: %v", i, err)
+		}
+	}
+
+	var (
+		ids  []int
+		prev *Document
+	)
+	for doc, err := range handler.Iter(gomysql.NewFilter().
+		KeyCmp(handler.FieldByGoName("BooleanField"), gomysql.OpEqual, true).
+		Ordering(handler.FieldByGoName("ID"), false)) {
+		if err != nil {
+			t.Fatalf("iteration failed: %v", err)
+		}
+		if doc == prev || !doc.BooleanField {
+			t.Fatalf("unexpected document %+v", doc)
+		}
+		prev = doc
+		ids = append(ids, doc.ID)
+	}
+
+	if len(ids) != 50 || ids[0] != 99 || ids[49] != 1 {
+		t.Fatalf("expected 50 even documents in descending order, got %v", ids)
+	}
+
+	seen := 0
+	for _, err := range handler.Iter(nil) {
+		if err != nil {
+			t.Fatalf("iteration failed: %v", err)
+		}
+		if seen++; seen == 3 {
+			break
+		}
+	}
+
+	// Breaking out of the loop releases the read lock, so writes proceed.
+	if err := handler.Insert(&Document{Title: "after break"}); err != nil {
+		t.Fatalf("failed to insert after breaking: %v", err)
+	}
+
+	for doc, err := range handler.Iter(gomysql.NewFilter().KeyCmp(handler.FieldByGoName("ID"), gomysql.OpEqual, 1).And()) {
+		if doc != nil || err == nil {
+			t.Fatalf("expected a filter error, got %+v (%v)", doc, err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var iterErr error
+	for _, err := range handler.IterCtx(ctx, nil) {
+		iterErr = err
+	}
+	if !errors.Is(iterErr, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", iterErr)
+	}
+}
+
+func TestForEach(t *testing.T) {
+	driver := openTestDriver(t, ":memory:")
+
+	handler, err := gomysql.RegisterOn(driver, Document{})
+	if err != nil {
+		t.Fatalf("failed to register Document struct: %v", err)
+	}
+
+	for i := range 10 {
+		if err := handler.Insert(&Document{Title: fmt.Sprintf("doc %d", i)}); err != nil {
+			t.Fatalf("failed to insert document %d: %v", i, err)
+		}
+	}
+
+	count := 0
+	if err := handler.ForEach(nil, func(doc *Document) error {
+		count++
+		return nil
+	}); err != nil || count != 10 {
+		t.Fatalf("expected 10 documents, got %d (%v)", count, err)
+	}
+
+	stop := errors.New("stop")
+	count = 0
+	if err := handler.ForEach(gomysql.NewFilter().Ordering(handler.FieldByGoName("ID"), true), func(doc *Document) error {
+		if count++; doc.ID == 4 {
+			return stop
+		}
+		return nil
+	}); !errors.Is(err, stop) || count != 4 {
+		t.Fatalf("expected ForEach to stop at the fourth document, got %d (%v)", count, err)
+	}
+}
+
+func TestIterWithReadPoolAllowsWrites(t *testing.T) {
+	driver, err := gomysql.Open(filepath.Join(t.TempDir(), "iter.db"), gomysql.DriverOptions{WAL: true, ReadPoolSize: 2})
+	if err != nil {
+		t.Fatalf("failed to open WAL database: %v", err)
+	}
+	defer driver.Close()
+
+	handler, err := gomysql.RegisterOn(driver, Document{})
+	if err != nil {
+		t.Fatalf("failed to register Document struct: %v", err)
+	}
+
+	for i := range 5 {
+		if err := handler.Insert(&Document{Title: fmt.Sprintf("doc %d", i)}); err != nil {
+			t.Fatalf("failed to insert document %d: %v", i, err)
+		}
+	}
+
+	if err := handler.ForEach(nil, func(doc *Document) error {
+		doc.Title += " copy"
+		doc.ID = 0
+		return handler.Insert(doc)
+	}); err != nil {
+		t.Fatalf("failed to write while streaming: %v", err)
+	}
+
+	if count, err := handler.Count(); err != nil || count < 10 {
+		t.Fatalf("expected the copies to be written, got %d (%v)", count, err)
+	}
+}
+
+func TestIterAllowsDriverCallsInsideLoop(t *testing.T) {
+	driver := openTestDriver(t, ":memory:")
+
+	handler, err := gomysql.RegisterOn(driver, Document{})
+	if err != nil {
+		t.Fatalf("failed to register Document struct: %v", err)
+	}
+
+	// Enough rows for several batches, so the keyset condition continues each one.
+	for i := range 1200 {
+		if err := handler.Insert(&Document{Title: fmt.Sprintf("doc %04d", i)}); err != nil {
+			t.Fatalf("failed to insert document %d: %v", i, err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var ids []int
+	for doc, err := range handler.IterCtx(ctx, gomysql.NewFilter().Ordering(handler.FieldByGoName("Title"), false)) {
+		if err != nil {
+			t.Fatalf("iteration failed: %v", err)
+		}
+		ids = append(ids, doc.ID)
+
+		if _, err := handler.SelectCtx(ctx, doc.ID); err != nil {
+			t.Fatalf("failed to read inside the loop: %v", err)
+		}
+
+		doc.BooleanField = true
+		if err := handler.UpdateCtx(ctx, doc); err != nil {
+			t.Fatalf("failed to write inside the loop: %v", err)
+		}
+	}
+
+	if len(ids) != 1200 || ids[0] != 1200 || ids[1199] != 1 {
+		t.Fatalf("expected 1200 documents in descending title order, got %d from %v", len(ids), ids[:min(len(ids), 3)])
+	}
+
+	updated, err := handler.CountWithFilter(gomysql.NewFilter().KeyCmp(handler.FieldByGoName("BooleanField"), gomysql.OpEqual, true))
+	if err != nil || updated != 1200 {
+		t.Fatalf("expected every document to be updated, got %d (%v)", updated, err)
+	}
+
+	count := 0
+	if err := handler.ForEachCtx(ctx, nil, func(doc *Document) error {
+		if count++; count <= 3 {
+			return handler.InsertCtx(ctx, &Document{Title: "inside"})
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("failed to insert inside ForEach: %v", err)
+	}
+
+	// Rows inserted during the loop sort after the current key, so they are visited too.
+	if count != 1203 {
+		t.Fatalf("expected 1203 documents, got %d", count)
+	}
+}
@@ -0,0 +1,195 @@
+package test
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/z46-dev/gomysql"
+	v1 "github.com/z46-dev/gomysql/test/migrationv1"
+	v2 "github.com/z46-dev/gomysql/test/migrationv2"
+)
+
+type JSONAddress struct {
+	City string `json:"city"`
+	Zip  string `json:"zip"`
+}
+
+type JSONProfile struct {
+	ID       int               `gomysql:"id,primary,increment"`
+	Address  JSONAddress       `gomysql:"address,json"`
+	Tags     []string          `gomysql:"tags,json"`
+	Settings map[string]any    `gomysql:"settings,json"`
+	Previous *JSONAddress      `gomysql:"previous,json"`
+	Legacy   map[string]string `gomysql:"legacy"`
+}
+
+type JSONDefaultProfile struct {
+	ID      int         `gomysql:"id,primary,increment"`
+	Address JSONAddress `gomysql:"address"`
+	Raw     []byte      `gomysql:"raw"`
+	Blob    []int       `gomysql:"blob,gob"`
+}
+
+type BadJSONField struct {
+	ID   int    `gomysql:"id,primary,increment"`
+	Name string `gomysql:"name,json"`
+}
+
+func openRawTestDriver(t *testing.T, opts gomysql.DriverOptions) (*sql.DB, *gomysql.Driver) {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "json.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+
+	driver, err := gomysql.OpenDB(db, opts)
+	if err != nil {
+		t.Fatalf("failed to wrap database: %v", err)
+	}
+	t.Cleanup(func() { driver.Close() })
+
+	return db, driver
+}
+
+func TestJSONColumns(t *testing.T) {
+	db, driver := openRawTestDriver(t, gomysql.DriverOptions{})
+
+	profiles, err := gomysql.RegisterOn(driver, JSONProfile{})
+	if err != nil {
+		t.Fatalf("failed to register json profile: %v", err)
+	}
+
+	items := []*JSONProfile{
+		{Address: JSONAddress{City: "Oslo", Zip: "0150"}, Tags: []string{"admin", "ops"}, Settings: map[string]any{"theme": "dark", "size": 14.0}},
+		{Address: JSONAddress{City: "Rome"}, Tags: []string{"ops"}, Previous: &JSONAddress{City: "Milan"}, Legacy: map[string]string{"a": "b"}},
+	}
+	for _, item := range items {
+		if err := profiles.Insert(item); err != nil {
+			t.Fatalf("failed to insert profile: %v", err)
+		}
+	}
+
+	var address, tags string
+	if err := db.QueryRow("SELECT address, tags FROM JSONProfile WHERE id = ?;", items[0].ID).Scan(&address, &tags); err != nil {
+		t.Fatalf("failed to read raw json: %v", err)
+	}
+
+	if address != `{"city":"Oslo","zip":"0150"}` || tags != `["admin","ops"]` {
+		t.Fatalf("expected readable json, got %s and %s", address, tags)
+	}
+
+	got, err := profiles.Select(items[1].ID)
+	if err != nil || got == nil {
+		t.Fatalf("failed to select profile: %v", err)
+	}
+
+	if got.Address != items[1].Address || got.Previous == nil || *got.Previous != *items[1].Previous || got.Legacy["a"] != "b" || got.Settings != nil {
+		t.Fatalf("unexpected profile: %+v", got)
+	}
+
+	if got, err = profiles.Select(items[0].ID); err != nil || got.Previous != nil || got.Settings["theme"] != "dark" || len(got.Tags) != 2 {
+		t.Fatalf("unexpected profile: %+v (%v)", got, err)
+	}
+
+	filtered, err := profiles.SelectAllWithFilter(gomysql.NewFilter().
+		JSONCmp(profiles.FieldByGoName("Address"), "$.city", gomysql.OpIn, []string{"Rome", "Paris"}).
+		Or().
+		JSONCmp(profiles.FieldByGoName("Settings"), "$.size", gomysql.OpGreaterThan, 20))
+	if err != nil {
+		t.Fatalf("failed to filter by json path: %v", err)
+	}
+
+	if len(filtered) != 1 || filtered[0].ID != items[1].ID {
+		t.Fatalf("expected the Rome profile, got %+v", filtered)
+	}
+
+	count, err := profiles.CountWithFilter(gomysql.NewFilter().
+		JSONCmp(profiles.FieldByGoName("Tags"), "$[0]", gomysql.OpEqual, "ops").
+		And().
+		JSONCmp(profiles.FieldByGoName("Previous"), "$.city", gomysql.OpIsNotNull, nil))
+	if err != nil || count != 1 {
+		t.Fatalf("expected 1 profile, got %d (%v)", count, err)
+	}
+
+	if _, err := gomysql.RegisterOn(driver, BadJSONField{}); err == nil {
+		t.Fatalf("expected json on a string field to be rejected")
+	}
+}
+
+func TestJSONDriverDefault(t *testing.T) {
+	db, driver := openRawTestDriver(t, gomysql.DriverOptions{JSON: true})
+
+	profiles, err := gomysql.RegisterOn(driver, JSONDefaultProfile{})
+	if err != nil {
+		t.Fatalf("failed to register profile: %v", err)
+	}
+
+	item := &JSONDefaultProfile{Address: JSONAddress{City: "Oslo"}, Raw: []byte{1, 2}, Blob: []int{3}}
+	if err := profiles.Insert(item); err != nil {
+		t.Fatalf("failed to insert profile: %v", err)
+	}
+
+	var address, raw, blob string
+	if err := db.QueryRow("SELECT typeof(address), typeof(raw), typeof(blob) FROM JSONDefaultProfile;").Scan(&address, &raw, &blob); err != nil {
+		t.Fatalf("failed to read column types: %v", err)
+	}
+
+	if address != "text" || raw != "blob" || blob != "blob" {
+		t.Fatalf("expected text, blob and blob storage, got %s, %s and %s", address, raw, blob)
+	}
+
+	got, err := profiles.Select(item.ID)
+	if err != nil || got.Address.City != "Oslo" || len(got.Raw) != 2 || got.Blob[0] != 3 {
+		t.Fatalf("unexpected profile: %+v (%v)", got, err)
+	}
+}
+
+func TestMigrationGobToJSON(t *testing.T) {
+	withTestDB(t, func() {
+		v1Handler, err := gomysql.Register(v1.JSONItem{})
+		if err != nil {
+			t.Fatalf("failed to register v1 struct: %v", err)
+		}
+
+		item := &v1.JSONItem{Tags: map[string]int{"a": 1, "b": 2}}
+		if err := v1Handler.Insert(item); err != nil {
+			t.Fatalf("failed to insert v1 item: %v", err)
+		}
+
+		empty := &v1.JSONItem{}
+		if err := v1Handler.Insert(empty); err != nil {
+			t.Fatalf("failed to insert empty v1 item: %v", err)
+		}
+
+		v2Handler, err := gomysql.Register(v2.JSONItem{})
+		if err != nil {
+			t.Fatalf("failed to register v2 struct: %v", err)
+		}
+
+		report, err := v2Handler.Migrate(gomysql.MigrationOptions{AllowDestructive: true})
+		if err != nil {
+			t.Fatalf("failed to migrate to json: %v", err)
+		}
+
+		if !report.Rebuilt || len(report.ChangedColumns) != 1 {
+			t.Fatalf("expected the tags column to be rebuilt, got %+v", report)
+		}
+
+		got, err := v2Handler.Select(item.ID)
+		if err != nil || got.Tags["a"] != 1 || got.Tags["b"] != 2 {
+			t.Fatalf("unexpected migrated item: %+v (%v)", got, err)
+		}
+
+		count, err := v2Handler.CountWithFilter(gomysql.NewFilter().JSONCmp(v2Handler.FieldByGoName("Tags"), "$.b", gomysql.OpEqual, 2))
+		if err != nil || count != 1 {
+			t.Fatalf("expected the migrated document to be queryable, got %d (%v)", count, err)
+		}
+
+		if got, err = v2Handler.Select(empty.ID); err != nil || len(got.Tags) != 0 {
+			t.Fatalf("unexpected migrated empty item: %+v (%v)", got, err)
+		}
+	})
+}
@@ -0,0 +1,155 @@
+package test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/z46-dev/gomysql"
+)
+
+func TestListKeys(t *testing.T) {
+	driver := openTestDriver(t, ":memory:")
+
+	handler, err := gomysql.RegisterOn(driver, Document{})
+	if err != nil {
+		t.Fatalf("failed to register Document struct: %v", err)
+	}
+
+	for i := range 5 {
+		if err := handler.Insert(&Document{Title: fmt.Sprintf("doc %d", i), BooleanField: i%2 == 0}); err != nil {
+			t.Fatalf("failed to insert document: %v", err)
+		}
+	}
+
+	even := gomysql.NewFilter().
+		KeyCmp(handler.FieldByGoName("BooleanField"), gomysql.OpEqual, true).
+		Ordering(handler.FieldByGoName("ID"), true)
+
+	listed, err := handler.ListWithFilter(even)
+	if err != nil {
+		t.Fatalf("failed to list keys: %v", err)
+	}
+	assert.Equal(t, []any{1, 3, 5}, listed, "expected keys of the declared int type")
+
+	ids, err := gomysql.ListKeys[int](handler, nil)
+	if err != nil || len(ids) != 5 {
+		t.Fatalf("failed to list every key: %v (%v)", ids, err)
+	}
+
+	wide, err := gomysql.ListKeys[int64](handler, even)
+	if err != nil || !slices.Equal(wide, []int64{1, 3, 5}) {
+		t.Fatalf("expected int64 keys, got %v (%v)", wide, err)
+	}
+
+	if _, err := gomysql.ListKeys[string](handler, nil); err == nil {
+		t.Fatalf("expected int keys to be rejected as strings")
+	}
+
+	roles, err := gomysql.RegisterOn(driver, UserRole{})
+	if err != nil {
+		t.Fatalf("failed to register UserRole struct: %v", err)
+	}
+
+	for _, role := range []*UserRole{{UserID: 1, RoleID: 1}, {UserID: 1, RoleID: 2}, {UserID: 2, RoleID: 1}} {
+		if err := roles.Insert(role); err != nil {
+			t.Fatalf("failed to insert role: %v", err)
+		}
+	}
+
+	byUser := gomysql.NewFilter().
+		KeyCmp(roles.FieldByGoName("UserID"), gomysql.OpEqual, 1).
+		Ordering(roles.FieldByGoName("RoleID"), true)
+
+	if listed, err = roles.ListWithFilter(byUser); err != nil {
+		t.Fatalf("failed to list composite keys: %v", err)
+	}
+	assert.Equal(t, []any{[]any{1, 1}, []any{1, 2}}, listed)
+
+	keys, err := gomysql.ListKeys[UserRoleKey](roles, byUser)
+	if err != nil {
+		t.Fatalf("failed to list composite keys as structs: %v", err)
+	}
+	assert.Equal(t, []UserRoleKey{{UserID: 1, RoleID: 1}, {UserID: 1, RoleID: 2}}, keys)
+
+	items, err := gomysql.ListKeys[UserRole](roles, byUser)
+	if err != nil || len(items) != 2 || items[1] != (UserRole{UserID: 1, RoleID: 2}) {
+		t.Fatalf("expected key fields of UserRole values, got %v (%v)", items, err)
+	}
+
+	tuples, err := gomysql.ListKeys[[]any](roles, nil)
+	if err != nil || len(tuples) != 3 {
+		t.Fatalf("failed to list composite keys as tuples: %v (%v)", tuples, err)
+	}
+
+	if _, err := gomysql.ListKeys[int](roles, nil); err == nil {
+		t.Fatalf("expected composite keys to be rejected as int")
+	}
+
+	if _, err := gomysql.ListKeys[struct{ UserID int }](roles, nil); err == nil {
+		t.Fatalf("expected a key struct missing a field to be rejected")
+	}
+}
+
+func TestSelectMany(t *testing.T) {
+	driver := openTestDriver(t, ":memory:")
+
+	handler, err := gomysql.RegisterOn(driver, Document{})
+	if err != nil {
+		t.Fatalf("failed to register Document struct: %v", err)
+	}
+
+	docs := make([]*Document, 1200)
+	for i := range docs {
+		docs[i] = &Document{Title: fmt.Sprintf("doc %d", i)}
+	}
+	if err := handler.InsertMany(docs, gomysql.InsertManyOptions{}); err != nil {
+		t.Fatalf("failed to insert documents: %v", err)
+	}
+
+	// Every odd key spans several chunks, plus keys without rows.
+	var keys []int
+	for id := 1; id <= 1300; id += 2 {
+		keys = append(keys, id)
+	}
+
+	found, err := gomysql.SelectMany(handler, keys)
+	if err != nil {
+		t.Fatalf("failed to select many: %v", err)
+	}
+
+	if len(found) != 600 {
+		t.Fatalf("expected 600 documents, got %d", len(found))
+	}
+	for _, doc := range found {
+		if doc.ID%2 != 1 || doc.Title != fmt.Sprintf("doc %d", doc.ID-1) {
+			t.Fatalf("unexpected document %+v", doc)
+		}
+	}
+
+	if found, err = gomysql.SelectMany(handler, []int{}); err != nil || found != nil {
+		t.Fatalf("expected no documents for no keys, got %v (%v)", found, err)
+	}
+
+	roles, err := gomysql.RegisterOn(driver, UserRole{})
+	if err != nil {
+		t.Fatalf("failed to register UserRole struct: %v", err)
+	}
+
+	for _, role := range []*UserRole{{UserID: 1, RoleID: 1, Note: "a"}, {UserID: 1, RoleID: 2, Note: "b"}, {UserID: 2, RoleID: 1, Note: "c"}} {
+		if err := roles.Insert(role); err != nil {
+			t.Fatalf("failed to insert role: %v", err)
+		}
+	}
+
+	byKey, err := gomysql.SelectMany(roles, []UserRoleKey{{UserID: 1, RoleID: 2}, {UserID: 2, RoleID: 1}, {UserID: 2, RoleID: 2}})
+	if err != nil || len(byKey) != 2 {
+		t.Fatalf("expected 2 roles by key struct, got %v (%v)", byKey, err)
+	}
+
+	byTuple, err := gomysql.SelectMany(roles, [][]any{{1, 1}})
+	if err != nil || len(byTuple) != 1 || byTuple[0].Note != "a" {
+		t.Fatalf("expected the role by tuple, got %v (%v)", byTuple, err)
+	}
+}
@@ -1,6 +1,8 @@
 package test
 
 import (
+	"context"
+	"errors"
 	"testing"
 
 	"github.com/z46-dev/gomysql"
@@ -223,3 +225,263 @@ func TestMigrationAddForeignKey(t *testing.T) {
 		}
 	})
 }
+
+func TestMigrationPrimaryKeyChange(t *testing.T) {
+	withTestDB(t, func() {
+		v1Handler, err := gomysql.Register(v1.KeyItem{})
+		if err != nil {
+			t.Fatalf("failed to register v1 struct: %v", err)
+		}
+
+		if err := v1Handler.Insert(&v1.KeyItem{Owner: "ada", Slot: 1, Label: "first"}); err != nil {
+			t.Fatalf("failed to insert v1 item: %v", err)
+		}
+
+		v2Handler, err := gomysql.Register(v2.KeyItem{})
+		if err != nil {
+			t.Fatalf("failed to register v2 struct: %v", err)
+		}
+
+		report, err := v2Handler.Migrate(gomysql.MigrationOptions{})
+		if err == nil {
+			t.Fatalf("expected primary key change to require AllowDestructive")
+		}
+
+		if !report.PrimaryKeyChanged {
+			t.Fatalf("expected report to flag the primary key change")
+		}
+
+		if report, err = v2Handler.Migrate(gomysql.MigrationOptions{AllowDestructive: true}); err != nil {
+			t.Fatalf("failed to migrate primary key: %v", err)
+		}
+
+		if !report.PrimaryKeyChanged || !report.Rebuilt {
+			t.Fatalf("expected rebuild for primary key change, got %+v", report)
+		}
+
+		if err := v2Handler.Insert(&v2.KeyItem{Owner: "ada", Slot: 2, Label: "second"}); err != nil {
+			t.Fatalf("expected second slot for the same owner to be allowed: %v", err)
+		}
+
+		got, err := v2Handler.Select([]any{"ada", 1})
+		if err != nil || got == nil {
+			t.Fatalf("failed to select migrated row: %v", err)
+		}
+
+		if got.Label != "first" {
+			t.Fatalf("expected migrated label, got %q", got.Label)
+		}
+
+		report, err = v2Handler.Migrate(gomysql.MigrationOptions{})
+		if err != nil || report.PrimaryKeyChanged {
+			t.Fatalf("expected no further primary key change, got %+v (%v)", report, err)
+		}
+	})
+}
+
+func TestMigrationAddNotNullColumnWithDefault(t *testing.T) {
+	withTestDB(t, func() {
+		v1Handler, err := gomysql.Register(v1.DefaultItem{})
+		if err != nil {
+			t.Fatalf("failed to register v1 struct: %v", err)
+		}
+
+		item := &v1.DefaultItem{Name: "charlie"}
+		if err := v1Handler.Insert(item); err != nil {
+			t.Fatalf("failed to insert v1 item: %v", err)
+		}
+
+		v2Handler, err := gomysql.Register(v2.DefaultItem{})
+		if err != nil {
+			t.Fatalf("failed to register v2 struct: %v", err)
+		}
+
+		report, err := v2Handler.Migrate(gomysql.MigrationOptions{})
+		if err != nil {
+			t.Fatalf("failed to migrate NOT NULL column with default: %v", err)
+		}
+
+		if len(report.AddedColumns) != 1 || report.AddedColumns[0] != "status" {
+			t.Fatalf("expected status to be added, got %+v", report)
+		}
+
+		got, err := v2Handler.Select(item.ID)
+		if err != nil {
+			t.Fatalf("failed to select after migration: %v", err)
+		}
+
+		if got.Status != "draft" {
+			t.Fatalf("expected existing row to get the default status, got %q", got.Status)
+		}
+
+		if err := v2Handler.Insert(&v2.DefaultItem{Name: "delta", Status: "bogus"}); err == nil {
+			t.Fatalf("expected CHECK constraint to reject an unknown status")
+		}
+
+		report, err = v2Handler.Migrate(gomysql.MigrationOptions{})
+		if err != nil || len(report.ChangedColumns) != 0 {
+			t.Fatalf("expected no drift after migrating, got %+v (%v)", report, err)
+		}
+	})
+}
+
+func TestMigrationDefaultAndCheckDrift(t *testing.T) {
+	withTestDB(t, func() {
+		v1Handler, err := gomysql.Register(v1.CheckItem{})
+		if err != nil {
+			t.Fatalf("failed to register v1 struct: %v", err)
+		}
+
+		if err := v1Handler.Insert(&v1.CheckItem{Priority: 0}); err == nil {
+			t.Fatalf("expected v1 CHECK constraint to reject priority 0")
+		}
+
+		if err := v1Handler.Insert(&v1.CheckItem{Priority: 3}); err != nil {
+			t.Fatalf("failed to insert v1 item: %v", err)
+		}
+
+		v2Handler, err := gomysql.Register(v2.CheckItem{})
+		if err != nil {
+			t.Fatalf("failed to register v2 struct: %v", err)
+		}
+
+		report, err := v2Handler.Migrate(gomysql.MigrationOptions{})
+		if !errors.Is(err, gomysql.ErrMigrationDestructive) {
+			t.Fatalf("expected default/check drift to require AllowDestructive, got %v", err)
+		}
+
+		if len(report.ChangedColumns) != 1 || report.ChangedColumns[0] != "priority" {
+			t.Fatalf("expected priority to be reported as changed, got %+v", report)
+		}
+
+		if _, err := v2Handler.Migrate(gomysql.MigrationOptions{AllowDestructive: true}); err != nil {
+			t.Fatalf("failed to migrate drift: %v", err)
+		}
+
+		if err := v2Handler.Insert(&v2.CheckItem{Priority: 0}); err != nil {
+			t.Fatalf("expected relaxed CHECK constraint to allow priority 0: %v", err)
+		}
+
+		report, err = v2Handler.Migrate(gomysql.MigrationOptions{})
+		if err != nil || len(report.ChangedColumns) != 0 {
+			t.Fatalf("expected no drift after migrating, got %+v (%v)", report, err)
+		}
+	})
+}
+
+func TestMigrationForeignKeyAction(t *testing.T) {
+	withTestDB(t, func() {
+		ownerHandler, err := gomysql.Register(v1.Owner{})
+		if err != nil {
+			t.Fatalf("failed to register owner struct: %v", err)
+		}
+
+		v1PetHandler, err := gomysql.Register(v1.Pet{})
+		if err != nil {
+			t.Fatalf("failed to register v1 pet struct: %v", err)
+		}
+
+		owner := &v1.Owner{Name: "owner"}
+		if err := ownerHandler.Insert(owner); err != nil {
+			t.Fatalf("failed to insert owner: %v", err)
+		}
+
+		pet := &v1.Pet{OwnerID: owner.ID}
+		if err := v1PetHandler.Insert(pet); err != nil {
+			t.Fatalf("failed to insert v1 pet: %v", err)
+		}
+
+		if err := ownerHandler.Delete(owner.ID); err == nil {
+			t.Fatalf("expected delete of a referenced owner to fail without ON DELETE CASCADE")
+		}
+
+		v2PetHandler, err := gomysql.Register(v2.Pet{})
+		if err != nil {
+			t.Fatalf("failed to register v2 pet struct: %v", err)
+		}
+
+		report, err := v2PetHandler.Migrate(gomysql.MigrationOptions{})
+		if !errors.Is(err, gomysql.ErrMigrationDestructive) {
+			t.Fatalf("expected action change to require AllowDestructive, got %v", err)
+		}
+
+		if len(report.ChangedColumns) != 1 || report.ChangedColumns[0] != "owner_id" {
+			t.Fatalf("expected owner_id to be reported as changed, got %+v", report)
+		}
+
+		if report, err = v2PetHandler.Migrate(gomysql.MigrationOptions{AllowDestructive: true}); err != nil || !report.Rebuilt {
+			t.Fatalf("expected rebuild for foreign key action change, got %+v (%v)", report, err)
+		}
+
+		if err := ownerHandler.Delete(owner.ID); err != nil {
+			t.Fatalf("failed to delete owner: %v", err)
+		}
+
+		if got, err := v2PetHandler.Select(pet.ID); err != nil || got != nil {
+			t.Fatalf("expected pet to be deleted by ON DELETE CASCADE, got %+v (%v)", got, err)
+		}
+
+		if report, err = v2PetHandler.Migrate(gomysql.MigrationOptions{}); err != nil || len(report.ChangedColumns) != 0 {
+			t.Fatalf("expected no drift after migrating, got %+v (%v)", report, err)
+		}
+
+		if _, err := gomysql.Register(v2.BadPet{}); err == nil {
+			t.Fatalf("expected SET NULL on a NOT NULL column to be rejected")
+		}
+	})
+}
+
+func TestMigrationRebuildKeepsCascadingChildren(t *testing.T) {
+	withTestDB(t, func() {
+		v1Kennels, err := gomysql.Register(v1.Kennel{})
+		if err != nil {
+			t.Fatalf("failed to register v1 kennel struct: %v", err)
+		}
+
+		dogs, err := gomysql.Register(v1.Dog{})
+		if err != nil {
+			t.Fatalf("failed to register dog struct: %v", err)
+		}
+
+		kennel := &v1.Kennel{Name: "kennel"}
+		if err := v1Kennels.Insert(kennel); err != nil {
+			t.Fatalf("failed to insert kennel: %v", err)
+		}
+
+		if err := dogs.Insert(&v1.Dog{KennelID: kennel.ID}); err != nil {
+			t.Fatalf("failed to insert dog: %v", err)
+		}
+
+		v2Kennels, err := gomysql.Register(v2.Kennel{})
+		if err != nil {
+			t.Fatalf("failed to register v2 kennel struct: %v", err)
+		}
+
+		// Inside a transaction foreign keys stay enforced, so the rebuild would cascade.
+		err = gomysql.DB.Tx(context.Background(), func(tx *gomysql.Tx) error {
+			_, err := v2Kennels.WithTx(tx).Migrate(gomysql.MigrationOptions{AllowDestructive: true})
+			return err
+		})
+		if err == nil {
+			t.Fatalf("expected rebuilding a referenced table inside a transaction to be refused")
+		}
+
+		report, err := v2Kennels.Migrate(gomysql.MigrationOptions{AllowDestructive: true})
+		if err != nil || !report.Rebuilt {
+			t.Fatalf("expected rebuild for the name change, got %+v (%v)", report, err)
+		}
+
+		if count, err := dogs.Count(); err != nil || count != 1 {
+			t.Fatalf("expected the dog to survive the kennel rebuild, got %d (%v)", count, err)
+		}
+
+		// Enforcement is back on after the rebuild.
+		if err := v2Kennels.Delete(kennel.ID); err != nil {
+			t.Fatalf("failed to delete kennel: %v", err)
+		}
+
+		if count, err := dogs.Count(); err != nil || count != 0 {
+			t.Fatalf("expected ON DELETE CASCADE to remove the dog, got %d (%v)", count, err)
+		}
+	})
+}
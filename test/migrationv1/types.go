@@ -25,3 +25,65 @@ type Child struct {
 	ID       int `gomysql:"id,primary,increment"`
 	ParentID int `gomysql:"parent_id"`
 }
+
+type KeyItem struct {
+	Owner string `gomysql:"owner,primary"`
+	Slot  int    `gomysql:"slot"`
+	Label string `gomysql:"label"`
+}
+
+type DefaultItem struct {
+	ID   int    `gomysql:"id,primary,increment"`
+	Name string `gomysql:"name"`
+}
+
+type CheckItem struct {
+	ID       int `gomysql:"id,primary,increment"`
+	Priority int `gomysql:"priority,notnull,default:1,check:priority > 0"`
+}
+
+type Owner struct {
+	ID   int    `gomysql:"id,primary,increment"`
+	Name string `gomysql:"name"`
+}
+
+type Pet struct {
+	ID      int `gomysql:"id,primary,increment"`
+	OwnerID int `gomysql:"owner_id,fkey:Owner.id"`
+}
+
+type IndexItem struct {
+	ID    int    `gomysql:"id,primary,increment"`
+	Name  string `gomysql:"name,index"`
+	Score int    `gomysql:"score"`
+}
+
+type ReservedItem struct {
+	ID    int    `gomysql:"id,primary,increment"`
+	Order int    `gomysql:"order"`
+	Group string `gomysql:"group,index"`
+}
+
+func (ReservedItem) TableName() string {
+	return "table"
+}
+
+type JSONItem struct {
+	ID   int            `gomysql:"id,primary,increment"`
+	Tags map[string]int `gomysql:"tags"`
+}
+
+type Kennel struct {
+	ID   int    `gomysql:"id,primary,increment"`
+	Name string `gomysql:"name"`
+}
+
+type Dog struct {
+	ID       int `gomysql:"id,primary,increment"`
+	KennelID int `gomysql:"kennel_id,fkey:Kennel.id,ondelete:cascade"`
+}
+
+// Gadget has no primary key, so registering it fails.
+type Gadget struct {
+	Name string `gomysql:"name"`
+}
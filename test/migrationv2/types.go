@@ -25,3 +25,71 @@ type Child struct {
 	ID       int `gomysql:"id,primary,increment"`
 	ParentID int `gomysql:"parent_id,fkey:Parent.id"`
 }
+
+type KeyItem struct {
+	Owner string `gomysql:"owner,primary"`
+	Slot  int    `gomysql:"slot,primary"`
+	Label string `gomysql:"label"`
+}
+
+type DefaultItem struct {
+	ID     int    `gomysql:"id,primary,increment"`
+	Name   string `gomysql:"name"`
+	Status string `gomysql:"status,notnull,default:'draft',check:status IN ('draft', 'live')"`
+}
+
+type CheckItem struct {
+	ID       int `gomysql:"id,primary,increment"`
+	Priority int `gomysql:"priority,notnull,default:2,check:priority >= 0"`
+}
+
+type Owner struct {
+	ID   int    `gomysql:"id,primary,increment"`
+	Name string `gomysql:"name"`
+}
+
+type Pet struct {
+	ID      int `gomysql:"id,primary,increment"`
+	OwnerID int `gomysql:"owner_id,fkey:Owner.id,ondelete:cascade,onupdate:no_action"`
+}
+
+type BadPet struct {
+	ID      int `gomysql:"id,primary,increment"`
+	OwnerID int `gomysql:"owner_id,notnull,fkey:Owner.id,ondelete:set null"`
+}
+
+type IndexItem struct {
+	ID    int    `gomysql:"id,primary,increment"`
+	Name  string `gomysql:"name,index:IndexItem_name_score"`
+	Score int    `gomysql:"score,index:IndexItem_name_score"`
+}
+
+type ReservedItem struct {
+	ID     int    `gomysql:"id,primary,increment"`
+	Order  string `gomysql:"order"`
+	Group  string `gomysql:"group,index"`
+	Select int    `gomysql:"select,notnull,default:0,check:\"select\" >= 0"`
+}
+
+func (ReservedItem) TableName() string {
+	return "table"
+}
+
+type JSONItem struct {
+	ID   int            `gomysql:"id,primary,increment"`
+	Tags map[string]int `gomysql:"tags,json"`
+}
+
+type Kennel struct {
+	ID   int    `gomysql:"id,primary,increment"`
+	Name string `gomysql:"name,notnull,default:'x'"`
+}
+
+type Gadget struct {
+	ID   int    `gomysql:"id,primary,increment"`
+	Name string `gomysql:"name"`
+}
+
+func (Gadget) TableName() string {
+	return "gadgets"
+}
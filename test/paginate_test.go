@@ -0,0 +1,167 @@
+package test
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/z46-dev/gomysql"
+)
+
+type Article struct {
+	ID    int    `gomysql:"id,primary,increment"`
+	Score int    `gomysql:"score,index"`
+	Title string `gomysql:"title"`
+}
+
+func TestPaginate(t *testing.T) {
+	driver := openTestDriver(t, ":memory:")
+
+	articles, err := gomysql.RegisterOn(driver, Article{})
+	if err != nil {
+		t.Fatalf("failed to register article: %v", err)
+	}
+
+	for i := range 25 {
+		if err := articles.Insert(&Article{Score: i % 5, Title: fmt.Sprintf("article %d", i)}); err != nil {
+			t.Fatalf("failed to insert article %d: %v", i, err)
+		}
+	}
+
+	all, err := articles.SelectAll()
+	if err != nil {
+		t.Fatalf("failed to select articles: %v", err)
+	}
+
+	// Highest score first, ties broken by ascending ID.
+	slices.SortFunc(all, func(a, b *Article) int {
+		if a.Score != b.Score {
+			return b.Score - a.Score
+		}
+		return a.ID - b.ID
+	})
+
+	var (
+		order   = []gomysql.PageOrder{{Field: articles.FieldByGoName("Score"), Desc: true}}
+		forward []int
+		pages   []*gomysql.Page[Article]
+		cursor  string
+	)
+	for {
+		page, err := articles.Paginate(nil, order, 7, cursor)
+		if err != nil {
+			t.Fatalf("failed to paginate: %v", err)
+		}
+
+		if (len(pages) == 0) != (page.Prev == "") {
+			t.Fatalf("expected a previous cursor on every page but the first")
+		}
+
+		pages = append(pages, page)
+		for _, item := range page.Items {
+			forward = append(forward, item.ID)
+		}
+
+		if cursor = page.Next; cursor == "" {
+			break
+		}
+	}
+
+	var expected []int
+	for _, item := range all {
+		expected = append(expected, item.ID)
+	}
+
+	if len(pages) != 4 || !slices.Equal(forward, expected) {
+		t.Fatalf("expected %v over 4 pages, got %v over %d", expected, forward, len(pages))
+	}
+
+	// Walking back from the last page returns the same pages.
+	cursor = pages[len(pages)-1].Prev
+	for i := len(pages) - 2; i >= 0; i-- {
+		page, err := articles.Paginate(nil, order, 7, cursor)
+		if err != nil {
+			t.Fatalf("failed to paginate backward: %v", err)
+		}
+
+		if len(page.Items) != len(pages[i].Items) || page.Items[0].ID != pages[i].Items[0].ID || page.Next == "" {
+			t.Fatalf("page %d differs walking backward: %+v", i, page.Items)
+		}
+
+		if (i == 0) != (page.Prev == "") {
+			t.Fatalf("expected no previous cursor only on the first page, got %q on page %d", page.Prev, i)
+		}
+		cursor = page.Prev
+	}
+
+	filter := gomysql.NewFilter().KeyCmp(articles.FieldByGoName("Score"), gomysql.OpGreaterThanOrEqual, 3)
+	page, err := articles.Paginate(filter, nil, 4, "")
+	if err != nil || len(page.Items) != 4 || page.Next == "" {
+		t.Fatalf("failed to paginate with a filter: %+v (%v)", page, err)
+	}
+
+	next, err := articles.Paginate(gomysql.NewFilter().KeyCmp(articles.FieldByGoName("Score"), gomysql.OpGreaterThanOrEqual, 3), nil, 4, page.Next)
+	if err != nil || len(next.Items) != 4 || next.Items[0].ID <= page.Items[3].ID {
+		t.Fatalf("failed to load the second filtered page: %+v (%v)", next, err)
+	}
+	for _, item := range append(page.Items, next.Items...) {
+		if item.Score < 3 {
+			t.Fatalf("unexpected article outside the filter: %+v", item)
+		}
+	}
+
+	if _, err := articles.Paginate(nil, nil, 4, page.Next); !errors.Is(err, gomysql.ErrInvalidCursor) {
+		t.Fatalf("expected a cursor of another filter to be rejected, got %v", err)
+	}
+
+	if _, err := articles.Paginate(filter, order, 4, page.Next); !errors.Is(err, gomysql.ErrInvalidCursor) {
+		t.Fatalf("expected a cursor of another order to be rejected, got %v", err)
+	}
+
+	tampered := []byte(page.Next)
+	tampered[len(tampered)/2] ^= 'A' ^ 'B'
+	if _, err := articles.Paginate(filter, nil, 4, string(tampered)); !errors.Is(err, gomysql.ErrInvalidCursor) {
+		t.Fatalf("expected a tampered cursor to be rejected, got %v", err)
+	}
+
+	if _, err := articles.Paginate(gomysql.NewFilter().
+		KeyCmp(articles.FieldByGoName("Score"), gomysql.OpEqual, 1).
+		Limit(5), nil, 4, ""); err == nil {
+		t.Fatalf("expected a filter with a limit to be rejected")
+	}
+}
+
+func TestPaginateCursorKey(t *testing.T) {
+	key := []byte("shared pagination secret")
+	open := func(opts gomysql.DriverOptions) *gomysql.RegisteredStruct[Article] {
+		_, driver := openRawTestDriver(t, opts)
+
+		articles, err := gomysql.RegisterOn(driver, Article{})
+		if err != nil {
+			t.Fatalf("failed to register article: %v", err)
+		}
+
+		for i := range 10 {
+			if err := articles.Insert(&Article{Score: i}); err != nil {
+				t.Fatalf("failed to insert article %d: %v", i, err)
+			}
+		}
+		return articles
+	}
+
+	first, second, other := open(gomysql.DriverOptions{CursorKey: key}), open(gomysql.DriverOptions{CursorKey: key}), open(gomysql.DriverOptions{})
+
+	page, err := first.Paginate(nil, nil, 3, "")
+	if err != nil || page.Next == "" {
+		t.Fatalf("failed to paginate: %+v (%v)", page, err)
+	}
+
+	if next, err := second.Paginate(nil, nil, 3, page.Next); err != nil || len(next.Items) != 3 || next.Items[0].ID != 4 {
+		t.Fatalf("expected a driver with the same key to accept the cursor, got %+v (%v)", next, err)
+	}
+
+	if _, err := other.Paginate(nil, nil, 3, page.Next); !errors.Is(err, gomysql.ErrInvalidCursor) {
+		t.Fatalf("expected a driver with another key to reject the cursor, got %v", err)
+	}
+}
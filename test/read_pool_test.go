@@ -0,0 +1,95 @@
+package test
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/z46-dev/gomysql"
+)
+
+func TestReadPoolOptionsValidation(t *testing.T) {
+	if _, err := gomysql.Open(filepath.Join(t.TempDir(), "no-wal.db"), gomysql.DriverOptions{ReadPoolSize: 2}); err == nil {
+		t.Fatalf("expected read pool without WAL to be rejected")
+	}
+
+	if _, err := gomysql.Open(":memory:", gomysql.DriverOptions{WAL: true, ReadPoolSize: 2}); err == nil {
+		t.Fatalf("expected read pool on an in-memory database to be rejected")
+	}
+}
+
+func TestReadPoolReadsDoNotWaitForWriter(t *testing.T) {
+	driver, err := gomysql.Open(filepath.Join(t.TempDir(), "wal.db"), gomysql.DriverOptions{WAL: true, ReadPoolSize: 4})
+	if err != nil {
+		t.Fatalf("failed to open WAL database: %v", err)
+	}
+	defer driver.Close()
+
+	handler, err := gomysql.RegisterOn(driver, Document{})
+	if err != nil {
+		t.Fatalf("failed to register Document struct: %v", err)
+	}
+
+	committed := &Document{Title: "committed"}
+	if err := handler.Insert(committed); err != nil {
+		t.Fatalf("failed to insert document: %v", err)
+	}
+
+	inserted := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- driver.Tx(context.Background(), func(tx *gomysql.Tx) error {
+			if err := handler.WithTx(tx).Insert(&Document{Title: "pending"}); err != nil {
+				return err
+			}
+			close(inserted)
+			<-release
+			return nil
+		})
+	}()
+
+	<-inserted
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			docs, err := handler.SelectAllCtx(ctx)
+			if err != nil {
+				t.Errorf("failed to read during open write transaction: %v", err)
+				return
+			}
+
+			if len(docs) != 1 || docs[0].Title != "committed" {
+				t.Errorf("expected only the committed document, got %d documents", len(docs))
+			}
+		}()
+	}
+	wg.Wait()
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("write transaction failed: %v", err)
+	}
+
+	count, err := handler.Count()
+	if err != nil {
+		t.Fatalf("failed to count documents: %v", err)
+	}
+
+	if count != 2 {
+		t.Fatalf("expected reads to see the committed transaction, got %d documents", count)
+	}
+
+	if err := handler.Delete(committed.ID); err != nil {
+		t.Fatalf("failed to delete through the writer connection: %v", err)
+	}
+}
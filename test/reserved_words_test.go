@@ -0,0 +1,161 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/z46-dev/gomysql"
+	v1 "github.com/z46-dev/gomysql/test/migrationv1"
+	v2 "github.com/z46-dev/gomysql/test/migrationv2"
+)
+
+type ReservedOrder struct {
+	ID     int    `gomysql:"id,primary,increment"`
+	Order  int    `gomysql:"order"`
+	Group  string `gomysql:"group,index"`
+	Select string `gomysql:"select,unique"`
+	Where  *int   `gomysql:"where"`
+}
+
+func (ReservedOrder) TableName() string {
+	return "order"
+}
+
+type ReservedLine struct {
+	ID      int `gomysql:"id,primary,increment"`
+	OrderID int `gomysql:"from,fkey:ReservedOrder.id,ondelete:cascade"`
+}
+
+func TestReservedWordIdentifiers(t *testing.T) {
+	withTestDB(t, func() {
+		orders, err := gomysql.Register(ReservedOrder{})
+		if err != nil {
+			t.Fatalf("failed to register reserved order: %v", err)
+		}
+
+		lines, err := gomysql.Register(ReservedLine{})
+		if err != nil {
+			t.Fatalf("failed to register reserved line: %v", err)
+		}
+
+		items := []*ReservedOrder{
+			{Order: 3, Group: "a", Select: "x"},
+			{Order: 1, Group: "b", Select: "y"},
+			{Order: 2, Group: "a", Select: "z"},
+		}
+		for _, item := range items {
+			if err := orders.Insert(item); err != nil {
+				t.Fatalf("failed to insert order: %v", err)
+			}
+		}
+
+		if err := lines.Insert(&ReservedLine{OrderID: items[0].ID}); err != nil {
+			t.Fatalf("failed to insert line: %v", err)
+		}
+
+		items[1].Order = 5
+		if err := orders.Update(items[1]); err != nil {
+			t.Fatalf("failed to update order: %v", err)
+		}
+
+		got, err := orders.Select(items[1].ID)
+		if err != nil || got.Order != 5 {
+			t.Fatalf("expected updated order 5, got %+v (%v)", got, err)
+		}
+
+		filtered, err := orders.SelectAllWithFilter(gomysql.NewFilter().
+			KeyCmp(orders.FieldBySQLName("group"), gomysql.OpEqual, "a").
+			And().
+			KeyCmp(orders.FieldBySQLName("where"), gomysql.OpIsNull, nil).
+			Ordering(orders.FieldBySQLName("order"), false))
+		if err != nil {
+			t.Fatalf("failed to select with filter: %v", err)
+		}
+
+		if len(filtered) != 2 || filtered[0].Order != 3 || filtered[1].Order != 2 {
+			t.Fatalf("unexpected filtered orders: %+v", filtered)
+		}
+
+		updated, err := orders.UpdateWithFilter(
+			gomysql.NewFilter().KeyCmp(orders.FieldBySQLName("select"), gomysql.OpIn, []string{"x", "z"}),
+			gomysql.SetAdd(orders.FieldBySQLName("order"), 10),
+		)
+		if err != nil || updated != 2 {
+			t.Fatalf("expected 2 updated rows, got %d (%v)", updated, err)
+		}
+
+		count, err := orders.CountWithFilter(gomysql.NewFilter().KeyCmp(orders.FieldBySQLName("order"), gomysql.OpGreaterThan, 10))
+		if err != nil || count != 2 {
+			t.Fatalf("expected 2 orders above 10, got %d (%v)", count, err)
+		}
+
+		deleted, err := orders.DeleteWithFilter(gomysql.NewFilter().
+			KeyCmp(orders.FieldBySQLName("group"), gomysql.OpEqual, "a").
+			Ordering(orders.FieldBySQLName("order"), true).
+			Limit(1))
+		if err != nil || deleted != 1 {
+			t.Fatalf("expected 1 deleted order, got %d (%v)", deleted, err)
+		}
+
+		if got, err := orders.Select(items[2].ID); err != nil || got != nil {
+			t.Fatalf("expected the lowest order in group a to be deleted, got %+v (%v)", got, err)
+		}
+
+		if err := orders.Delete(items[0].ID); err != nil {
+			t.Fatalf("failed to delete order: %v", err)
+		}
+
+		if remaining, err := lines.Count(); err != nil || remaining != 0 {
+			t.Fatalf("expected the line to cascade, got %d (%v)", remaining, err)
+		}
+	})
+}
+
+func TestReservedWordMigration(t *testing.T) {
+	withTestDB(t, func() {
+		v1Handler, err := gomysql.Register(v1.ReservedItem{})
+		if err != nil {
+			t.Fatalf("failed to register v1 struct: %v", err)
+		}
+
+		item := &v1.ReservedItem{Order: 7, Group: "g"}
+		if err := v1Handler.Insert(item); err != nil {
+			t.Fatalf("failed to insert v1 item: %v", err)
+		}
+
+		v2Handler, err := gomysql.Register(v2.ReservedItem{})
+		if err != nil {
+			t.Fatalf("failed to register v2 struct: %v", err)
+		}
+
+		report, err := v2Handler.Migrate(gomysql.MigrationOptions{AllowDestructive: true})
+		if err != nil {
+			t.Fatalf("failed to migrate reserved item: %v", err)
+		}
+
+		if !report.Rebuilt {
+			t.Fatalf("expected the type change to rebuild the table, got %+v", report)
+		}
+
+		got, err := v2Handler.Select(item.ID)
+		if err != nil {
+			t.Fatalf("failed to select after migration: %v", err)
+		}
+
+		if got.Order != "7" || got.Group != "g" || got.Select != 0 {
+			t.Fatalf("unexpected migrated item: %+v", got)
+		}
+
+		if err := v2Handler.Insert(&v2.ReservedItem{Select: -1}); err == nil {
+			t.Fatalf("expected the check constraint to reject a negative select")
+		}
+
+		report, err = v2Handler.Migrate(gomysql.MigrationOptions{})
+		if err != nil {
+			t.Fatalf("failed to re-run migration: %v", err)
+		}
+
+		if report.Rebuilt || len(report.ChangedColumns) > 0 || len(report.AddedIndexes) > 0 {
+			t.Fatalf("expected no drift after migration, got %+v", report)
+		}
+	})
+}
@@ -0,0 +1,96 @@
+package test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/z46-dev/gomysql"
+)
+
+func TestSelectFields(t *testing.T) {
+	driver := openTestDriver(t, ":memory:")
+
+	handler, err := gomysql.RegisterOn(driver, Document{})
+	if err != nil {
+		t.Fatalf("failed to register Document struct: %v", err)
+	}
+
+	for _, title := range []string{"alpha", "beta", "gamma"} {
+		if err := handler.Insert(&Document{Title: title, Body: "long body", Tags: []string{"tag"}, Creation: time.Now(), BooleanField: true}); err != nil {
+			t.Fatalf("failed to insert document: %v", err)
+		}
+	}
+
+	docs, err := handler.SelectFields(
+		gomysql.NewFilter().
+			KeyCmp(handler.FieldByGoName("Title"), gomysql.OpNotEqual, "beta").
+			Ordering(handler.FieldByGoName("ID"), false),
+		handler.FieldByGoName("ID"), handler.FieldByGoName("Title"))
+	if err != nil {
+		t.Fatalf("failed to select fields: %v", err)
+	}
+
+	if len(docs) != 2 || docs[0].Title != "gamma" || docs[0].ID != 3 || docs[1].Title != "alpha" {
+		t.Fatalf("unexpected documents %+v", docs)
+	}
+
+	for _, doc := range docs {
+		if doc.Body != "" || doc.Tags != nil || !doc.Creation.IsZero() || doc.BooleanField {
+			t.Fatalf("expected unrequested fields to stay zero, got %+v", doc)
+		}
+	}
+
+	if docs, err = handler.SelectFields(nil, handler.FieldByGoName("Tags")); err != nil || len(docs) != 3 || !slices.Equal(docs[0].Tags, []string{"tag"}) || docs[0].ID != 0 {
+		t.Fatalf("expected the tags of every document, got %+v (%v)", docs, err)
+	}
+
+	if _, err := handler.SelectFields(nil); err == nil {
+		t.Fatalf("expected SelectFields without fields to fail")
+	}
+
+	articles, err := gomysql.RegisterOn(driver, Article{})
+	if err != nil {
+		t.Fatalf("failed to register article: %v", err)
+	}
+
+	if _, err := handler.SelectFields(nil, articles.FieldByGoName("Title")); err == nil {
+		t.Fatalf("expected a field of another struct to be rejected")
+	}
+}
+
+func TestPluck(t *testing.T) {
+	driver := openTestDriver(t, ":memory:")
+
+	articles, err := gomysql.RegisterOn(driver, Article{})
+	if err != nil {
+		t.Fatalf("failed to register article: %v", err)
+	}
+
+	for i, title := range []string{"one", "two", "three"} {
+		if err := articles.Insert(&Article{Score: (i + 1) * 100, Title: title}); err != nil {
+			t.Fatalf("failed to insert article: %v", err)
+		}
+	}
+
+	byID := gomysql.NewFilter().Ordering(articles.FieldByGoName("ID"), true)
+	titles, err := gomysql.Pluck[string](articles, articles.FieldByGoName("Title"), byID)
+	if err != nil || !slices.Equal(titles, []string{"one", "two", "three"}) {
+		t.Fatalf("unexpected titles %v (%v)", titles, err)
+	}
+
+	scores, err := gomysql.Pluck[int64](articles, articles.FieldByGoName("Score"), gomysql.NewFilter().
+		KeyCmp(articles.FieldByGoName("Score"), gomysql.OpGreaterThan, 100))
+	if err != nil || len(scores) != 2 || !slices.Contains(scores, 300) {
+		t.Fatalf("unexpected scores %v (%v)", scores, err)
+	}
+
+	if _, err := gomysql.Pluck[uint8](articles, articles.FieldByGoName("Score"), nil); err == nil || !strings.Contains(err.Error(), "overflows") {
+		t.Fatalf("expected scores above 255 to overflow uint8, got %v", err)
+	}
+
+	if _, err := gomysql.Pluck[string](articles, articles.FieldByGoName("Score"), nil); err == nil {
+		t.Fatalf("expected plucking an int field as a string to fail")
+	}
+}
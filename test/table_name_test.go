@@ -0,0 +1,250 @@
+package test
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/z46-dev/gomysql"
+	v1 "github.com/z46-dev/gomysql/test/migrationv1"
+	v2 "github.com/z46-dev/gomysql/test/migrationv2"
+)
+
+type LegacyUser struct {
+	ID   int    `gomysql:"id,primary,increment"`
+	Name string `gomysql:"name,unique"`
+}
+
+func (LegacyUser) TableName() string {
+	return "tbl_users"
+}
+
+type LegacyNote struct {
+	ID     int    `gomysql:"id,primary,increment"`
+	UserID int    `gomysql:"user_id,fkey:LegacyUser.id,ondelete:cascade"`
+	Body   string `gomysql:"body,index"`
+}
+
+func (*LegacyNote) TableName() string {
+	return "tbl_notes"
+}
+
+type ArchiveGroup struct {
+	ID   int    `gomysql:"id,primary,increment"`
+	Name string `gomysql:"name"`
+}
+
+func (ArchiveGroup) TableName() string {
+	return "archive.groups"
+}
+
+type ArchiveMember struct {
+	ID      int    `gomysql:"id,primary,increment"`
+	GroupID int    `gomysql:"group_id,fkey:ArchiveGroup.id"`
+	Name    string `gomysql:"name,index"`
+	Legacy  string `gomysql:"legacy"`
+}
+
+func (ArchiveMember) TableName() string {
+	return "archive.members"
+}
+
+type ArchiveMemberV2 struct {
+	ID      int    `gomysql:"id,primary,increment"`
+	GroupID int    `gomysql:"group_id,fkey:ArchiveGroup.id"`
+	Name    string `gomysql:"name,index"`
+}
+
+func (ArchiveMemberV2) TableName() string {
+	return "archive.members"
+}
+
+type BadTableName struct {
+	ID int `gomysql:"id,primary,increment"`
+}
+
+func (BadTableName) TableName() string {
+	return "a.b.c"
+}
+
+func TestTableNameOverride(t *testing.T) {
+	withTestDB(t, func() {
+		users, err := gomysql.Register(LegacyUser{})
+		if err != nil {
+			t.Fatalf("failed to register legacy user: %v", err)
+		}
+
+		notes, err := gomysql.Register(LegacyNote{})
+		if err != nil {
+			t.Fatalf("failed to register legacy note: %v", err)
+		}
+
+		if users.Name != "tbl_users" || notes.Name != "tbl_notes" {
+			t.Fatalf("expected overridden table names, got %s and %s", users.Name, notes.Name)
+		}
+
+		user := &LegacyUser{Name: "ada"}
+		if err := users.Insert(user); err != nil {
+			t.Fatalf("failed to insert user: %v", err)
+		}
+
+		note := &LegacyNote{UserID: user.ID, Body: "hello"}
+		if err := notes.Insert(note); err != nil {
+			t.Fatalf("failed to insert note: %v", err)
+		}
+
+		if err := notes.Insert(&LegacyNote{UserID: user.ID + 100, Body: "orphan"}); err == nil {
+			t.Fatalf("expected fkey to resolve to tbl_users and reject a missing user")
+		}
+
+		found, err := notes.SelectAllWithFilter(gomysql.NewFilter().KeyCmp(notes.FieldByGoName("Body"), gomysql.OpEqual, "hello"))
+		if err != nil || len(found) != 1 {
+			t.Fatalf("expected one note by filter, got %d (%v)", len(found), err)
+		}
+
+		report, err := notes.Migrate(gomysql.MigrationOptions{})
+		if err != nil || report.Table != "tbl_notes" || len(report.ChangedColumns) != 0 || len(report.DroppedIndexes) != 0 {
+			t.Fatalf("expected no changes for tbl_notes, got %+v (%v)", report, err)
+		}
+
+		if err := users.Delete(user.ID); err != nil {
+			t.Fatalf("failed to delete user: %v", err)
+		}
+
+		if count, err := notes.Count(); err != nil || count != 0 {
+			t.Fatalf("expected note to cascade, got %d (%v)", count, err)
+		}
+
+		if _, err := gomysql.Register(BadTableName{}); err == nil {
+			t.Fatalf("expected a table name with two prefixes to be rejected")
+		}
+	})
+}
+
+type GadgetPart struct {
+	ID       int `gomysql:"id,primary,increment"`
+	GadgetID int `gomysql:"gadget_id,fkey:Gadget.id"`
+}
+
+type TreeNode struct {
+	ID       int  `gomysql:"id,primary,increment"`
+	ParentID *int `gomysql:"parent_id,fkey:TreeNode.id"`
+}
+
+func (TreeNode) TableName() string {
+	return "tree_nodes"
+}
+
+func TestFailedRegistrationKeepsTableFree(t *testing.T) {
+	driver := openTestDriver(t, ":memory:")
+
+	if _, err := gomysql.RegisterOn(driver, v1.Gadget{}); err == nil {
+		t.Fatalf("expected a struct without a primary key to be rejected")
+	}
+
+	gadgets, err := gomysql.RegisterOn(driver, v2.Gadget{})
+	if err != nil {
+		t.Fatalf("failed to register the corrected gadget: %v", err)
+	}
+
+	parts, err := gomysql.RegisterOn(driver, GadgetPart{})
+	if err != nil {
+		t.Fatalf("expected fkey:Gadget to resolve to the corrected table, got %v", err)
+	}
+
+	gadget := &v2.Gadget{Name: "gadget"}
+	if err := gadgets.Insert(gadget); err != nil {
+		t.Fatalf("failed to insert gadget: %v", err)
+	}
+
+	if err := parts.Insert(&GadgetPart{GadgetID: gadget.ID + 1}); err == nil {
+		t.Fatalf("expected the foreign key to reference gadgets")
+	}
+
+	nodes, err := gomysql.RegisterOn(driver, TreeNode{})
+	if err != nil {
+		t.Fatalf("failed to register self-referencing struct: %v", err)
+	}
+
+	root := &TreeNode{}
+	if err := nodes.Insert(root); err != nil {
+		t.Fatalf("failed to insert root node: %v", err)
+	}
+
+	if err := nodes.Insert(&TreeNode{ParentID: &root.ID}); err != nil {
+		t.Fatalf("failed to insert child node: %v", err)
+	}
+}
+
+func TestAttachedDatabaseTables(t *testing.T) {
+	dir := t.TempDir()
+
+	db, err := sql.Open("sqlite", filepath.Join(dir, "main.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec("ATTACH DATABASE ? AS archive;", filepath.Join(dir, "archive.db")); err != nil {
+		t.Fatalf("failed to attach archive: %v", err)
+	}
+
+	driver, err := gomysql.OpenDB(db, gomysql.DriverOptions{})
+	if err != nil {
+		t.Fatalf("failed to wrap database: %v", err)
+	}
+	defer driver.Close()
+
+	groups, err := gomysql.RegisterOn(driver, ArchiveGroup{})
+	if err != nil {
+		t.Fatalf("failed to register archive group: %v", err)
+	}
+
+	members, err := gomysql.RegisterOn(driver, ArchiveMember{})
+	if err != nil {
+		t.Fatalf("failed to register archive member: %v", err)
+	}
+
+	group := &ArchiveGroup{Name: "old"}
+	if err := groups.Insert(group); err != nil {
+		t.Fatalf("failed to insert group: %v", err)
+	}
+
+	member := &ArchiveMember{GroupID: group.ID, Name: "grace", Legacy: "x"}
+	if err := members.Insert(member); err != nil {
+		t.Fatalf("failed to insert member: %v", err)
+	}
+
+	var tables int
+	if err := db.QueryRow("SELECT COUNT(*) FROM archive.sqlite_master WHERE name IN ('groups', 'members', 'members_name_idx');").Scan(&tables); err != nil || tables != 3 {
+		t.Fatalf("expected tables and index in the attached database, got %d (%v)", tables, err)
+	}
+
+	membersV2, err := gomysql.RegisterOn(driver, ArchiveMemberV2{})
+	if err != nil {
+		t.Fatalf("failed to register archive member v2: %v", err)
+	}
+
+	report, err := membersV2.Migrate(gomysql.MigrationOptions{AllowDestructive: true})
+	if err != nil {
+		t.Fatalf("failed to migrate archive members: %v", err)
+	}
+
+	if !report.Rebuilt || len(report.DroppedColumns) != 1 || len(report.ChangedColumns) != 0 {
+		t.Fatalf("expected legacy to be dropped by a rebuild, got %+v", report)
+	}
+
+	got, err := membersV2.Select(member.ID)
+	if err != nil || got == nil || got.Name != "grace" {
+		t.Fatalf("failed to select migrated member: %+v (%v)", got, err)
+	}
+
+	if err := membersV2.Insert(&ArchiveMemberV2{GroupID: group.ID + 100, Name: "orphan"}); err == nil {
+		t.Fatalf("expected foreign key to survive the rebuild")
+	}
+
+	report, err = membersV2.Migrate(gomysql.MigrationOptions{})
+	if err != nil || len(report.AddedIndexes) != 0 || len(report.DroppedIndexes) != 0 || len(report.ChangedColumns) != 0 {
+		t.Fatalf("expected no further changes, got %+v (%v)", report, err)
+	}
+}
@@ -0,0 +1,180 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/z46-dev/gomysql"
+	v2 "github.com/z46-dev/gomysql/test/migrationv2"
+)
+
+type txHandlers struct {
+	driver   *gomysql.Driver
+	parents  *gomysql.RegisteredStruct[v2.Parent]
+	children *gomysql.RegisteredStruct[v2.Child]
+}
+
+func newTxHandlers(t *testing.T) txHandlers {
+	t.Helper()
+
+	driver := openTestDriver(t, ":memory:")
+
+	parents, err := gomysql.RegisterOn(driver, v2.Parent{})
+	if err != nil {
+		t.Fatalf("failed to register parent struct: %v", err)
+	}
+
+	children, err := gomysql.RegisterOn(driver, v2.Child{})
+	if err != nil {
+		t.Fatalf("failed to register child struct: %v", err)
+	}
+
+	return txHandlers{driver: driver, parents: parents, children: children}
+}
+
+func TestTxCommitsAcrossTypes(t *testing.T) {
+	h := newTxHandlers(t)
+
+	parent := &v2.Parent{Name: "parent"}
+	err := h.driver.Tx(context.Background(), func(tx *gomysql.Tx) error {
+		if err := h.parents.WithTx(tx).Insert(parent); err != nil {
+			return err
+		}
+
+		return h.children.WithTx(tx).Insert(&v2.Child{ParentID: parent.ID})
+	})
+	if err != nil {
+		t.Fatalf("transaction failed: %v", err)
+	}
+
+	children, err := h.children.SelectAll()
+	if err != nil {
+		t.Fatalf("failed to select children: %v", err)
+	}
+
+	if len(children) != 1 || children[0].ParentID != parent.ID {
+		t.Fatalf("expected one child of parent %d, got %+v", parent.ID, children)
+	}
+}
+
+func TestTxRollsBackOnError(t *testing.T) {
+	h := newTxHandlers(t)
+
+	errAbort := errors.New("abort")
+	err := h.driver.Tx(context.Background(), func(tx *gomysql.Tx) error {
+		parent := &v2.Parent{Name: "parent"}
+		if err := h.parents.WithTx(tx).Insert(parent); err != nil {
+			return err
+		}
+
+		if err := h.children.WithTx(tx).Insert(&v2.Child{ParentID: parent.ID}); err != nil {
+			return err
+		}
+
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("expected abort error, got %v", err)
+	}
+
+	if count, err := h.parents.Count(); err != nil || count != 0 {
+		t.Fatalf("expected no parents after rollback, got %d (%v)", count, err)
+	}
+
+	if count, err := h.children.Count(); err != nil || count != 0 {
+		t.Fatalf("expected no children after rollback, got %d (%v)", count, err)
+	}
+}
+
+func TestTxRollsBackOnPanic(t *testing.T) {
+	h := newTxHandlers(t)
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatalf("expected panic to propagate out of Tx")
+			}
+		}()
+
+		_ = h.driver.Tx(context.Background(), func(tx *gomysql.Tx) error {
+			if err := h.parents.WithTx(tx).Insert(&v2.Parent{Name: "parent"}); err != nil {
+				return err
+			}
+			panic("boom")
+		})
+	}()
+
+	if count, err := h.parents.Count(); err != nil || count != 0 {
+		t.Fatalf("expected no parents after panic, got %d (%v)", count, err)
+	}
+}
+
+func TestTxSavepoint(t *testing.T) {
+	h := newTxHandlers(t)
+
+	err := h.driver.Tx(context.Background(), func(tx *gomysql.Tx) error {
+		parents := h.parents.WithTx(tx)
+		if err := parents.Insert(&v2.Parent{Name: "kept"}); err != nil {
+			return err
+		}
+
+		err := tx.Savepoint(func(tx *gomysql.Tx) error {
+			if err := parents.Insert(&v2.Parent{Name: "discarded"}); err != nil {
+				return err
+			}
+
+			return tx.Savepoint(func(tx *gomysql.Tx) error {
+				return h.children.WithTx(tx).Insert(&v2.Child{ParentID: 999})
+			})
+		})
+		if err == nil {
+			return errors.New("expected foreign key violation inside savepoint")
+		}
+
+		return tx.Savepoint(func(tx *gomysql.Tx) error {
+			return parents.Insert(&v2.Parent{Name: "nested"})
+		})
+	})
+	if err != nil {
+		t.Fatalf("transaction failed: %v", err)
+	}
+
+	parents, err := h.parents.SelectAll()
+	if err != nil {
+		t.Fatalf("failed to select parents: %v", err)
+	}
+
+	var names []string
+	for _, parent := range parents {
+		names = append(names, parent.Name)
+	}
+
+	if len(names) != 2 || names[0] != "kept" || names[1] != "nested" {
+		t.Fatalf("expected kept and nested parents, got %v", names)
+	}
+}
+
+func TestTxSavepointUsesTxContext(t *testing.T) {
+	h := newTxHandlers(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var savepointErr error
+	err := h.driver.Tx(ctx, func(tx *gomysql.Tx) error {
+		cancel()
+		savepointErr = tx.Savepoint(func(tx *gomysql.Tx) error {
+			return h.parents.WithTx(tx).Insert(&v2.Parent{Name: "never"})
+		})
+		return savepointErr
+	})
+
+	if !errors.Is(savepointErr, context.Canceled) || !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the savepoint to stop with the cancelled context, got %v (%v)", savepointErr, err)
+	}
+
+	if count, err := h.parents.Count(); err != nil || count != 0 {
+		t.Fatalf("expected no parents, got %d (%v)", count, err)
+	}
+}
@@ -0,0 +1,111 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/z46-dev/gomysql"
+)
+
+type UpsertTeam struct {
+	ID    int    `gomysql:"id,primary,increment"`
+	Name  string `gomysql:"name,unique"`
+	Motto string `gomysql:"motto"`
+	Wins  int    `gomysql:"wins"`
+}
+
+func TestInsertFailsOnConflict(t *testing.T) {
+	handler, err := gomysql.RegisterOn(openTestDriver(t, ":memory:"), UpsertTeam{})
+	if err != nil {
+		t.Fatalf("failed to register UpsertTeam struct: %v", err)
+	}
+
+	first := &UpsertTeam{Name: "red", Motto: "first"}
+	if err := handler.Insert(first); err != nil {
+		t.Fatalf("failed to insert team: %v", err)
+	}
+
+	assert.Error(t, handler.Insert(&UpsertTeam{Name: "red", Motto: "second"}), "expected unique conflict")
+
+	stored, err := handler.Select(first.ID)
+	if err != nil {
+		t.Fatalf("failed to select team: %v", err)
+	}
+	assert.Equal(t, "first", stored.Motto)
+}
+
+func TestInsertOrIgnore(t *testing.T) {
+	handler, err := gomysql.RegisterOn(openTestDriver(t, ":memory:"), UpsertTeam{})
+	if err != nil {
+		t.Fatalf("failed to register UpsertTeam struct: %v", err)
+	}
+
+	first := &UpsertTeam{Name: "red", Motto: "first"}
+	inserted, err := handler.InsertOrIgnore(first)
+	if err != nil {
+		t.Fatalf("failed to insert team: %v", err)
+	}
+	assert.True(t, inserted)
+	assert.NotZero(t, first.ID)
+
+	inserted, err = handler.InsertOrIgnore(&UpsertTeam{Name: "red", Motto: "second"})
+	if err != nil {
+		t.Fatalf("failed to insert conflicting team: %v", err)
+	}
+	assert.False(t, inserted)
+
+	count, err := handler.Count()
+	if err != nil {
+		t.Fatalf("failed to count teams: %v", err)
+	}
+	assert.EqualValues(t, 1, count)
+}
+
+func TestUpsertInsertsThenUpdates(t *testing.T) {
+	handler, err := gomysql.RegisterOn(openTestDriver(t, ":memory:"), UpsertTeam{})
+	if err != nil {
+		t.Fatalf("failed to register UpsertTeam struct: %v", err)
+	}
+
+	var (
+		name  = []*gomysql.RegisteredStructField{handler.FieldByGoName("Name")}
+		first = &UpsertTeam{Name: "red", Motto: "first", Wins: 1}
+	)
+
+	inserted, err := handler.Upsert(first, name)
+	if err != nil {
+		t.Fatalf("failed to upsert new team: %v", err)
+	}
+	assert.True(t, inserted)
+	assert.NotZero(t, first.ID)
+
+	second := &UpsertTeam{Name: "red", Motto: "second", Wins: 5}
+	inserted, err = handler.Upsert(second, name, handler.FieldByGoName("Wins"))
+	if err != nil {
+		t.Fatalf("failed to upsert existing team: %v", err)
+	}
+	assert.False(t, inserted)
+	assert.Equal(t, first.ID, second.ID, "expected the existing key to be assigned")
+
+	stored, err := handler.Select(first.ID)
+	if err != nil {
+		t.Fatalf("failed to select team: %v", err)
+	}
+	assert.Equal(t, "first", stored.Motto, "expected columns outside updateFields to be kept")
+	assert.Equal(t, 5, stored.Wins)
+
+	third := &UpsertTeam{Name: "red", Motto: "third", Wins: 9}
+	if _, err := handler.Upsert(third, name); err != nil {
+		t.Fatalf("failed to upsert existing team: %v", err)
+	}
+
+	stored, err = handler.Select(first.ID)
+	if err != nil {
+		t.Fatalf("failed to select team: %v", err)
+	}
+	assert.Equal(t, "third", stored.Motto, "expected all non-conflict columns to be updated")
+	assert.Equal(t, 9, stored.Wins)
+
+	_, err = handler.Upsert(&UpsertTeam{Name: "blue"}, nil)
+	assert.Error(t, err, "expected missing conflict fields to be rejected")
+}
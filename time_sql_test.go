@@ -1,6 +1,7 @@
 package gomysql
 
 import (
+	"context"
 	"strings"
 	"testing"
 	"time"
@@ -39,7 +40,7 @@ func TestTimeFieldUsesDateTimeColumn(t *testing.T) {
 			t.Fatalf("failed to register struct: %v", err)
 		}
 
-		cols, err := handler.db.tableColumns(handler.Name)
+		cols, err := tableColumns(context.Background(), handler.db.db, handler.Name)
 		if err != nil {
 			t.Fatalf("failed to inspect columns: %v", err)
 		}
@@ -0,0 +1,223 @@
+package gomysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+type sqlExecutor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// Tx is a database transaction shared by every registered struct bound to it with WithTx.
+// The driver lock is held for the lifetime of the transaction, so registered structs that
+// are not bound to the transaction must not be used from inside the callback.
+type Tx struct {
+	driver     *Driver
+	tx         *sql.Tx
+	ctx        context.Context // the context the transaction was begun with
+	savepoints int
+	rollbacks  []func() // undo changes made to caller values, run if the work is rolled back
+}
+
+// Tx runs fn inside a transaction. The transaction is committed if fn returns nil and
+// rolled back if fn returns an error or panics.
+func (d *Driver) Tx(ctx context.Context, fn func(tx *Tx) error) error {
+	if d == nil {
+		return ErrDatabaseNotInitialized
+	}
+
+	if err := d.lockContext(ctx); err != nil {
+		return err
+	}
+	defer d.lock.Unlock()
+
+	return d.runTx(ctx, fn)
+}
+
+func (d *Driver) runTx(ctx context.Context, fn func(tx *Tx) error) (err error) {
+	sqlTx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+
+	tx := &Tx{
+		driver: d,
+		tx:     sqlTx,
+		ctx:    ctx,
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = sqlTx.Rollback()
+			tx.undo(0)
+			panic(p)
+		}
+	}()
+
+	if err = fn(tx); err != nil {
+		tx.undo(0)
+		if rollbackErr := sqlTx.Rollback(); rollbackErr != nil {
+			return fmt.Errorf("%w (rollback: %v)", err, rollbackErr)
+		}
+		return err
+	}
+
+	if err = sqlTx.Commit(); err != nil {
+		tx.undo(0)
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// onRollback registers fn to run if the work done so far, in the transaction or the current
+// savepoint, is rolled back.
+func (t *Tx) onRollback(fn func()) {
+	t.rollbacks = append(t.rollbacks, fn)
+}
+
+// undo runs the rollback functions registered after the first n, newest first, and drops them.
+func (t *Tx) undo(n int) {
+	for i := len(t.rollbacks) - 1; i >= n; i-- {
+		t.rollbacks[i]()
+	}
+	t.rollbacks = t.rollbacks[:n]
+}
+
+// Savepoint runs fn inside a nested SAVEPOINT of the transaction. Work done by fn is
+// rolled back to the savepoint if fn returns an error or panics, leaving the outer
+// transaction usable. The savepoint statements run with the transaction's context.
+func (t *Tx) Savepoint(fn func(tx *Tx) error) error {
+	return t.savepoint(t.ctx, fn)
+}
+
+// savepoint runs fn inside a savepoint whose statements run with ctx.
+func (t *Tx) savepoint(ctx context.Context, fn func(tx *Tx) error) (err error) {
+	t.savepoints++
+	name := fmt.Sprintf("gomysql_sp_%d", t.savepoints)
+	defer func() {
+		t.savepoints--
+	}()
+
+	if _, err = t.tx.ExecContext(ctx, "SAVEPOINT "+name+";"); err != nil {
+		return fmt.Errorf("savepoint %s: %w", name, err)
+	}
+
+	registered := len(t.rollbacks)
+	rollback := func() error {
+		t.undo(registered)
+		if _, err := t.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name+";"); err != nil {
+			return err
+		}
+		_, err := t.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+name+";")
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = rollback()
+			panic(p)
+		}
+	}()
+
+	if err = fn(t); err != nil {
+		if rollbackErr := rollback(); rollbackErr != nil {
+			return fmt.Errorf("%w (rollback to %s: %v)", err, name, rollbackErr)
+		}
+		return err
+	}
+
+	if _, err = t.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+name+";"); err != nil {
+		return fmt.Errorf("release savepoint %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// prepare prepares query on the transaction with the dialect's placeholders. Arguments must
+// still be bound with dialectExecutor.bindArgs.
+func (t *Tx) prepare(ctx context.Context, query string) (*sql.Stmt, error) {
+	return t.tx.PrepareContext(ctx, t.driver.dialect.rebind(query))
+}
+
+func (t *Tx) executor() sqlExecutor {
+	return dialectExecutor{q: t.tx, dialect: t.driver.dialect}
+}
+
+// WithTx returns a view of the registered struct whose operations run inside tx.
+func (r *RegisteredStruct[T]) WithTx(tx *Tx) *RegisteredStruct[T] {
+	if tx == nil {
+		panic("WithTx requires a valid transaction")
+	}
+
+	if tx.driver != r.db {
+		panic(fmt.Sprintf("WithTx transaction belongs to a different driver than %s", r.Name))
+	}
+
+	view := *r
+	view.tx = tx
+	return &view
+}
+
+// executor returns the executor for r's statements. Outside a transaction statements are
+// prepared once and reused through the driver's statement cache.
+func (r *RegisteredStruct[T]) executor() sqlExecutor {
+	if r.tx == nil && r.db.stmts != nil {
+		return stmtExecutor{cache: r.db.stmts, owner: r.Name}
+	}
+
+	return r.directExecutor()
+}
+
+// directExecutor bypasses the statement cache, for one-off statements such as DDL.
+func (r *RegisteredStruct[T]) directExecutor() sqlExecutor {
+	if r.tx != nil {
+		return r.tx.executor()
+	}
+
+	return dialectExecutor{q: r.db.db, dialect: r.db.dialect}
+}
+
+func (r *RegisteredStruct[T]) acquire(ctx context.Context) (release func(), err error) {
+	if r.tx != nil {
+		return func() {}, nil
+	}
+
+	if err = r.db.lockContext(ctx); err != nil {
+		return nil, err
+	}
+
+	return r.db.lock.Unlock, nil
+}
+
+// readExecutor returns the executor for read-only statements. Reads inside a transaction use
+// the transaction, reads with a WAL read pool use the pool without taking the driver lock,
+// and all other reads share the driver's read lock.
+func (r *RegisteredStruct[T]) readExecutor(ctx context.Context) (q sqlExecutor, release func(), err error) {
+	switch {
+	case r.tx != nil:
+		return r.tx.executor(), func() {}, nil
+	case r.db.readStmts != nil:
+		return stmtExecutor{cache: r.db.readStmts, owner: r.Name}, func() {}, nil
+	case r.db.readDB != nil:
+		return dialectExecutor{q: r.db.readDB, dialect: r.db.dialect}, func() {}, nil
+	}
+
+	if err = r.db.rlockContext(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	return r.executor(), r.db.lock.RUnlock, nil
+}
+
+func (r *RegisteredStruct[T]) inTx(ctx context.Context, fn func(tx *Tx) error) error {
+	if r.tx != nil {
+		return r.tx.savepoint(ctx, fn)
+	}
+
+	return r.db.runTx(ctx, fn)
+}
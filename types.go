@@ -11,16 +11,21 @@ type RegisteredStructField struct {
 	Type         reflect.Type
 	Index        []int
 	InternalType TypeRepresentation
+	codec        *fieldCodec // custom conversion, nil for the representation's own
 }
 
 type RegisteredStruct[T any] struct {
 	db                                                                                *Driver
+	tx                                                                                *Tx
 	Name                                                                              string
 	Type                                                                              reflect.Type
 	Fields                                                                            []RegisteredStructField
 	createTableSQL, insertSQL, selectSQL, updateSQL, deleteSQL, listSQL, selectAllSQL string
-	PrimaryKeyField                                                                   RegisteredStructField
+	insertOrIgnoreSQL                                                                 string
+	PrimaryKeyField                                                                   RegisteredStructField   // first primary key field
+	PrimaryKeyFields                                                                  []RegisteredStructField // every primary key field, in declaration order
 	insertOrdered, nonInsertionOrdered                                                []RegisteredStructField
+	indexes                                                                           []Index
 }
 
 type TypeRepresentation uint8
@@ -36,6 +41,8 @@ const (
 	TypeRepMapBlob
 	TypeRepPointer
 	TypeRepTime
+	TypeRepJSON
+	TypeRepCustom // stored by a codec registered with RegisterCodec
 )
 
 func typeNameString(t TypeRepresentation) string {
@@ -54,6 +61,8 @@ func typeNameString(t TypeRepresentation) string {
 		return "FLOAT"
 	case TypeRepTime:
 		return "DATETIME"
+	case TypeRepJSON:
+		return "TEXT"
 	default:
 		return "UNKNOWN"
 	}
@@ -62,6 +71,7 @@ func typeNameString(t TypeRepresentation) string {
 var (
 	ErrDatabaseInitialized    = fmt.Errorf("database already initialized")
 	ErrDatabaseNotInitialized = fmt.Errorf("database not initialized")
+	ErrUnsupportedByDialect   = fmt.Errorf("operation not supported by dialect")
 )
 
 type SQLOperator string
@@ -84,6 +94,7 @@ type Filter struct {
 	args                                     []any
 	whereTokens                              []string
 	orderByClause, limitClause, offsetClause string
+	order                                    *PageOrder // the field of orderByClause
 	lastWasJoiner                            bool
 }
 
@@ -94,6 +105,26 @@ type UpdateAssignment struct {
 
 type ReturnedValues map[string]any
 
+type InsertManyOptions struct {
+	// BatchSize caps the rows per INSERT statement. Zero fits as many rows as the dialect's
+	// bound parameter limit allows.
+	BatchSize int
+	// ContinueOnError commits the items that could be inserted and skips the ones that
+	// failed. By default one failure rolls back the whole batch.
+	ContinueOnError bool
+}
+
+// InsertItemError is the failure of a single item passed to InsertMany.
+type InsertItemError struct {
+	Index int
+	Err   error
+}
+
+// InsertManyError lists the items InsertMany could not insert, ordered by index.
+type InsertManyError struct {
+	Items []InsertItemError
+}
+
 func (o SQLTagOpts) HasForeignKey() bool {
 	return o.ForeignKey != nil
 }
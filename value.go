@@ -3,7 +3,9 @@ package gomysql
 import (
 	"bytes"
 	"encoding/gob"
+	"encoding/json"
 	"fmt"
+	"math"
 	"reflect"
 	"time"
 )
@@ -25,12 +27,43 @@ func derefValue(v reflect.Value) (reflect.Value, bool) {
 	return v.Elem(), true
 }
 
+// setInteger stores the integer v, of any width and signedness, in the integer target,
+// failing instead of wrapping when it does not fit.
+func setInteger(target, v reflect.Value) error {
+	if !(v.CanInt() || v.CanUint()) || !(target.CanInt() || target.CanUint()) {
+		return fmt.Errorf("cannot store %s in %s", v.Type(), target.Type())
+	}
+
+	switch {
+	case v.CanInt() && target.CanInt() && !target.OverflowInt(v.Int()):
+		target.SetInt(v.Int())
+	case v.CanInt() && target.CanUint() && v.Int() >= 0 && !target.OverflowUint(uint64(v.Int())):
+		target.SetUint(uint64(v.Int()))
+	case v.CanUint() && target.CanUint() && !target.OverflowUint(v.Uint()):
+		target.SetUint(v.Uint())
+	case v.CanUint() && target.CanInt() && v.Uint() <= math.MaxInt64 && !target.OverflowInt(int64(v.Uint())):
+		target.SetInt(int64(v.Uint()))
+	default:
+		return fmt.Errorf("%v overflows %s", v, target.Type())
+	}
+
+	return nil
+}
+
 func getSQLValueOf(field RegisteredStructField, structField reflect.Value) (any, error) {
 	value, ok := derefValue(structField)
 	if !ok {
 		return nil, nil
 	}
 
+	if field.codec != nil && field.codec.encode != nil {
+		encoded, err := field.codec.encode(value)
+		if err != nil {
+			return nil, fmt.Errorf("encoding %s: %w", field.Opts.KeyName, err)
+		}
+		return encoded, nil
+	}
+
 	switch field.InternalType {
 	case TypeRepInt:
 		return value.Int(), nil
@@ -39,7 +72,7 @@ func getSQLValueOf(field RegisteredStructField, structField reflect.Value) (any,
 	case TypeRepBool:
 		return value.Bool(), nil
 	case TypeRepTime:
-		return formatSQLTimeValue(value.Interface().(time.Time)), nil
+		return value.Interface().(time.Time).UTC(), nil
 	case TypeRepArrayBlob, TypeRepStructBlob, TypeRepMapBlob:
 		fieldBaseType := baseTypeOf(field.Type)
 		if field.InternalType == TypeRepArrayBlob && fieldBaseType.Kind() == reflect.Slice && fieldBaseType.Elem().Kind() == reflect.String {
@@ -51,6 +84,13 @@ func getSQLValueOf(field RegisteredStructField, structField reflect.Value) (any,
 		}
 
 		return buf.Bytes(), nil
+	case TypeRepJSON:
+		encoded, err := json.Marshal(value.Interface())
+		if err != nil {
+			return nil, fmt.Errorf("json encoding %s: %w", field.Opts.KeyName, err)
+		}
+
+		return string(encoded), nil
 	case TypeRepFloat:
 		return value.Float(), nil
 	case TypeRepUint:
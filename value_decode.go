@@ -3,7 +3,9 @@ package gomysql
 import (
 	"bytes"
 	"encoding/gob"
+	"encoding/json"
 	"fmt"
+	"math"
 	"reflect"
 	"strconv"
 	"strings"
@@ -76,8 +78,14 @@ func sqlInt64(raw any) (int64, error) {
 	case int8:
 		return int64(v), nil
 	case uint64:
+		if v > math.MaxInt64 {
+			return 0, fmt.Errorf("%d overflows int64", v)
+		}
 		return int64(v), nil
 	case uint:
+		if uint64(v) > math.MaxInt64 {
+			return 0, fmt.Errorf("%d overflows int64", v)
+		}
 		return int64(v), nil
 	case uint32:
 		return int64(v), nil
@@ -108,17 +116,16 @@ func sqlUint64(raw any) (uint64, error) {
 		return uint64(v), nil
 	case uint8:
 		return uint64(v), nil
-	case int64:
-		return uint64(v), nil
-	case int:
-		return uint64(v), nil
-	case int32:
-		return uint64(v), nil
-	case int16:
-		return uint64(v), nil
-	case int8:
-		return uint64(v), nil
+	case int64, int, int32, int16, int8:
+		signed := reflect.ValueOf(v).Int()
+		if signed < 0 {
+			return 0, fmt.Errorf("%d overflows uint64", signed)
+		}
+		return uint64(signed), nil
 	case []byte:
+		if value, ok := decodeUint64(v); ok {
+			return value, nil
+		}
 		return strconv.ParseUint(string(v), 10, 64)
 	case string:
 		return strconv.ParseUint(v, 10, 64)
@@ -175,6 +182,7 @@ func assignDecodedValue(fieldValue reflect.Value, field RegisteredStructField, r
 			Type:         fieldValue.Type().Elem(),
 			Index:        field.Index,
 			InternalType: field.InternalType,
+			codec:        field.codec,
 		}, raw); err != nil {
 			return err
 		}
@@ -182,19 +190,30 @@ func assignDecodedValue(fieldValue reflect.Value, field RegisteredStructField, r
 		return nil
 	}
 
+	if field.codec != nil && field.codec.decode != nil {
+		if err := field.codec.decode(raw, fieldValue); err != nil {
+			return fmt.Errorf("decode %s: %w", field.Opts.KeyName, err)
+		}
+		return nil
+	}
+
 	switch field.InternalType {
 	case TypeRepInt:
 		value, err := sqlInt64(raw)
+		if err == nil {
+			err = setInteger(fieldValue, reflect.ValueOf(value))
+		}
 		if err != nil {
 			return fmt.Errorf("convert %s to int: %w", field.Opts.KeyName, err)
 		}
-		fieldValue.SetInt(value)
 	case TypeRepUint:
 		value, err := sqlUint64(raw)
+		if err == nil {
+			err = setInteger(fieldValue, reflect.ValueOf(value))
+		}
 		if err != nil {
 			return fmt.Errorf("convert %s to uint: %w", field.Opts.KeyName, err)
 		}
-		fieldValue.SetUint(value)
 	case TypeRepString:
 		value, err := sqlString(raw)
 		if err != nil {
@@ -271,6 +290,19 @@ func assignDecodedValue(fieldValue reflect.Value, field RegisteredStructField, r
 		if err := gob.NewDecoder(bytes.NewReader(raw.([]byte))).Decode(fieldValue.Addr().Interface()); err != nil {
 			return fmt.Errorf("gob decode %s: %w", field.Opts.KeyName, err)
 		}
+	case TypeRepJSON:
+		fieldValue.Set(reflect.Zero(fieldValue.Type()))
+		if raw == nil {
+			return nil
+		}
+
+		encoded, err := sqlString(raw)
+		if err != nil {
+			return fmt.Errorf("convert %s to json: %w", field.Opts.KeyName, err)
+		}
+		if err := json.Unmarshal([]byte(encoded), fieldValue.Addr().Interface()); err != nil {
+			return fmt.Errorf("json decode %s: %w", field.Opts.KeyName, err)
+		}
 	default:
 		return fmt.Errorf("unsupported type for field %s", field.Opts.KeyName)
 	}
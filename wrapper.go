@@ -1,7 +1,12 @@
 package gomysql
 
 import (
+	"context"
+	"crypto/rand"
 	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
 	"sync"
 
 	_ "modernc.org/sqlite"
@@ -10,37 +15,240 @@ import (
 var DB *Driver
 
 type Driver struct {
-	db       *sql.DB
-	lock     *sync.RWMutex
-	filePath string
+	db        *sql.DB
+	readDB    *sql.DB
+	stmts     *stmtCache
+	readStmts *stmtCache
+	lock      *sync.RWMutex
+	tablesMu  sync.Mutex
+	tables    map[string]string // struct name -> table name, for resolving fkey: targets
+	filePath  string
+	opts      DriverOptions
+	dialect   Dialect
+	cursorKey []byte // signs Paginate cursors
 }
 
-func Begin(dbPath string) (err error) {
-	if DB != nil {
-		err = ErrDatabaseInitialized
+// Open opens dataSourceName with the database/sql driver registered for the dialect in opts.
+// Drivers for dialects other than SQLite must be imported by the caller. SQLite options are
+// passed to the driver so they apply to every new connection.
+func Open(dataSourceName string, opts DriverOptions) (driver *Driver, err error) {
+	var (
+		db       *sql.DB
+		dialect  = opts.dialect()
+		openName = dataSourceName
+	)
+
+	if err = opts.Validate(); err != nil {
 		return
 	}
 
-	var db *sql.DB
+	if dialect == SQLite {
+		openName = sqliteDSN(dataSourceName, sqlitePragmas(opts, false))
+	}
+
+	if db, err = sql.Open(dialect.driverName(), openName); err != nil {
+		return
+	}
 
-	if db, err = sql.Open("sqlite", dbPath); err != nil {
+	if driver, err = newDriver(db, opts); err != nil {
+		_ = db.Close()
 		return
-	} else {
-		db.SetMaxOpenConns(1)
-		db.SetMaxIdleConns(1)
+	}
 
-		if _, err = db.Exec("PRAGMA foreign_keys = ON;"); err != nil {
+	driver.filePath = dataSourceName
+
+	if opts.ReadPoolSize > 0 {
+		if driver.readDB, err = openReadPool(dataSourceName, opts); err != nil {
 			_ = db.Close()
-			return
+			return nil, err
+		}
+
+		if size := opts.statementCacheSize(); size > 0 {
+			driver.readStmts = newStmtCache(driver.readDB, dialect, size)
+		}
+	}
+
+	return
+}
+
+func openReadPool(dataSourceName string, opts DriverOptions) (*sql.DB, error) {
+	if strings.Contains(dataSourceName, ":memory:") || strings.Contains(dataSourceName, "mode=memory") {
+		return nil, fmt.Errorf("read pool requires a file-backed database")
+	}
+
+	readDB, err := sql.Open("sqlite", sqliteDSN(dataSourceName, sqlitePragmas(opts, true)))
+	if err != nil {
+		return nil, err
+	}
+
+	readDB.SetMaxOpenConns(opts.ReadPoolSize)
+	readDB.SetMaxIdleConns(opts.ReadPoolSize)
+
+	if err := readDB.Ping(); err != nil {
+		_ = readDB.Close()
+		return nil, fmt.Errorf("read pool: %w", err)
+	}
+
+	return readDB, nil
+}
+
+// OpenDB wraps an already opened database handle. The driver takes ownership of db and
+// closes it in Close. SQLite options are applied with PRAGMA statements on the handle's
+// single connection; ReadPoolSize requires Open.
+func OpenDB(db *sql.DB, opts DriverOptions) (driver *Driver, err error) {
+	if err = opts.Validate(); err != nil {
+		return
+	}
+
+	if opts.ReadPoolSize > 0 {
+		err = fmt.Errorf("read pool requires Open")
+		return
+	}
+
+	if opts.dialect() == SQLite {
+		for _, pragma := range sqlitePragmas(opts, false) {
+			if _, err = db.Exec("PRAGMA " + pragma + ";"); err != nil {
+				return
+			}
 		}
+	}
 
-		DB = &Driver{
-			db:       db,
-			lock:     &sync.RWMutex{},
-			filePath: dbPath,
+	return newDriver(db, opts)
+}
+
+func newDriver(db *sql.DB, opts DriverOptions) (driver *Driver, err error) {
+	var dialect = opts.dialect()
+
+	if err = dialect.configure(db, opts); err != nil {
+		return
+	}
+
+	driver = &Driver{
+		db:        db,
+		lock:      &sync.RWMutex{},
+		opts:      opts,
+		dialect:   dialect,
+		cursorKey: slices.Clone(opts.CursorKey),
+	}
+
+	if len(driver.cursorKey) == 0 {
+		driver.cursorKey = make([]byte, 32)
+		if _, err = rand.Read(driver.cursorKey); err != nil {
+			return nil, fmt.Errorf("generate cursor key: %w", err)
 		}
 	}
 
+	if size := opts.statementCacheSize(); size > 0 {
+		driver.stmts = newStmtCache(db, dialect, size)
+	}
+
+	return
+}
+
+func (d *Driver) Dialect() Dialect {
+	return d.dialect
+}
+
+// registerTable records the table a struct is stored in. Several structs with the same name
+// mapping to different tables make the name ambiguous as an fkey: target.
+func (d *Driver) registerTable(structName, table string) {
+	d.tablesMu.Lock()
+	defer d.tablesMu.Unlock()
+
+	if d.tables == nil {
+		d.tables = make(map[string]string)
+	}
+
+	if existing, ok := d.tables[structName]; ok && existing != table {
+		table = ""
+	}
+	d.tables[structName] = table
+}
+
+// resolveTable maps an fkey: target to its table. Names of unregistered structs are used as
+// table names as they are.
+func (d *Driver) resolveTable(target string) (string, error) {
+	d.tablesMu.Lock()
+	defer d.tablesMu.Unlock()
+
+	table, ok := d.tables[target]
+	switch {
+	case !ok:
+		return target, nil
+	case table == "":
+		return "", fmt.Errorf("foreign key target %s is ambiguous: several registered structs share the name", target)
+	default:
+		return table, nil
+	}
+}
+
+func (d *Driver) Close() (err error) {
+	if d == nil {
+		err = ErrDatabaseNotInitialized
+		return
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	for _, cache := range []*stmtCache{d.stmts, d.readStmts} {
+		if cache != nil {
+			cache.close()
+		}
+	}
+
+	if d.readDB != nil {
+		if err = d.readDB.Close(); err != nil {
+			_ = d.db.Close()
+			return
+		}
+	}
+	err = d.db.Close()
+	return
+}
+
+func (d *Driver) lockContext(ctx context.Context) error {
+	return acquireContext(ctx, d.lock.Lock, d.lock.Unlock)
+}
+
+func (d *Driver) rlockContext(ctx context.Context) error {
+	return acquireContext(ctx, d.lock.RLock, d.lock.RUnlock)
+}
+
+func acquireContext(ctx context.Context, lock, unlock func()) error {
+	if ctx.Done() == nil {
+		lock()
+		return nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	acquired := make(chan struct{})
+	go func() {
+		lock()
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+		return nil
+	case <-ctx.Done():
+		go func() {
+			<-acquired
+			unlock()
+		}()
+		return ctx.Err()
+	}
+}
+
+func Begin(dbPath string) (err error) {
+	if DB != nil {
+		err = ErrDatabaseInitialized
+		return
+	}
+
+	DB, err = Open(dbPath, DriverOptions{})
 	return
 }
 
@@ -50,9 +258,7 @@ func Close() (err error) {
 		return
 	}
 
-	DB.lock.Lock()
-	defer DB.lock.Unlock()
-	if err = DB.db.Close(); err != nil {
+	if err = DB.Close(); err != nil {
 		return
 	}
 